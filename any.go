@@ -0,0 +1,158 @@
+package yy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Select is rule for choosing result of ParseAny from results of several layouts
+type Select int
+
+// Selection rules
+const (
+	SelectFirst   Select = iota // first layout, which matches and resolves
+	SelectPrecise               // most precise result (seconds are more precise than days)
+	SelectNearest               // result nearest to reference time
+)
+
+// AmbiguousError is returned, when data resolves to materially different dates
+type AmbiguousError struct {
+	Data  string
+	Times []time.Time // different resolutions of data
+}
+
+func (e *AmbiguousError) Error() string {
+	s := make([]string, len(e.Times))
+	for i, t := range e.Times {
+		s[i] = t.String()
+	}
+	return fmt.Sprintf("ambiguous date %q: %s", e.Data, strings.Join(s, ", "))
+}
+
+// precision returns precision of date from its components:
+// 0 year, 1 month, 2 day, 3 hour, 4 minute, 5 second, 6 fraction
+func precision(p *IDate) int {
+	switch {
+	case p.F.Present():
+		return 6
+	case p.S.Present() || p.R.Present() && p.R.Period().clock():
+		return 5
+	case p.M.Present():
+		return 4
+	case p.H.Present():
+		return 3
+	case p.D.Present() || p.J.Present() || p.WD.Present() || p.R.Present():
+		return 2
+	case p.Mo.Present() || p.WN.Present():
+		return 1
+	}
+	return 0
+}
+
+// truncate returns t truncated to precision
+func truncate(t time.Time, prec int) time.Time {
+	y, m, d := t.Date()
+	v := [...]int{y, int(m), d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond()}
+	min := [...]int{0, 1, 1, 0, 0, 0, 0}
+	for i := prec + 1; i < len(v); i++ {
+		v[i] = min[i]
+	}
+	return time.Date(v[0], time.Month(v[1]), v[2], v[3], v[4], v[5], v[6], t.Location())
+}
+
+// same returns if a and b are same date, at lower of precisions pa and pb
+func same(a, b time.Time, pa, pb int) bool {
+	if pb < pa {
+		pa = pb
+	}
+	return truncate(a, pa).Equal(truncate(b, pa))
+}
+
+// result of parsing with one of layouts
+type result struct {
+	t    time.Time
+	prec int // precision
+	i    int // index of layout
+}
+
+// ambiguous returns error for different results
+func ambiguous(data string, all []result) error {
+	e := &AmbiguousError{Data: data}
+	for _, a := range all {
+		dup := false
+		for _, t := range e.Times {
+			dup = dup || t.Equal(a.t)
+		}
+		if !dup {
+			e.Times = append(e.Times, a.t)
+		}
+	}
+	return e
+}
+
+// ParseAny parses data with each of layouts, and chooses from results by rule r.Select.
+// Returns resolved time and index of chosen layout.
+//
+// Results equal at precision of less precise of them ("2013-06-03" and "2013-06-03 10:20")
+// are consistent, if any results are not consistent, *AmbiguousError is returned.
+// If data is one of r.Sentinels, its time is returned with index -1,
+// or error for sentinel mapped to null, as in Parse.
+func (r *Resolver) ParseAny(data string, layouts []Layout, rt time.Time) (time.Time, int, error) {
+	if t, ok := r.sentinel(data); ok {
+		t, err := notNull(t, nil)
+		return t, -1, err
+	}
+
+	var all []result
+	var first error
+	for i, l := range layouts {
+		p, err := l.parse(data, r)
+		var t time.Time
+		if err == nil {
+			t, err = r.Convert(rt, &p)
+		}
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		all = append(all, result{t, precision(&p), i})
+	}
+	if len(all) == 0 {
+		if first == nil {
+			first = errNoMatch
+		}
+		return time.Time{}, -1, first
+	}
+
+	for i, a := range all {
+		for _, b := range all[:i] {
+			if !same(a.t, b.t, a.prec, b.prec) {
+				return time.Time{}, -1, ambiguous(data, all)
+			}
+		}
+	}
+
+	best := all[0]
+	for _, a := range all[1:] {
+		switch r.Select {
+		case SelectPrecise:
+			if a.prec > best.prec {
+				best = a
+			}
+		case SelectNearest:
+			if abs(int64(a.t.Sub(rt))) < abs(int64(best.t.Sub(rt))) {
+				best = a
+			}
+		}
+	}
+	return best.t, best.i, nil
+}
+
+// ParseAny parses data with each of layouts, like Resolver.ParseAny with first match rule
+func ParseAny(data string, layouts []Layout, ref time.Time) (time.Time, int, error) {
+	var r Resolver
+	return r.ParseAny(data, layouts, ref)
+}
@@ -0,0 +1,92 @@
+package yy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAny(t *testing.T) {
+	layouts := []Layout{
+		MustCompile("YYYY-MM-DD[ hh:mm]"),
+		MustCompile("YYMMDD[hhmm]"),
+		MustCompile("MM/DD/YY"),
+		MustCompile("DD/MM/YY"),
+		MustCompile("DD.MM.YYYY"),
+		MustCompile("YYYY-MM"),
+	}
+
+	tests := []struct {
+		in   string
+		sel  Select
+		out  time.Time
+		i    int
+		fail bool
+	}{
+		{in: "2013-06-03", out: time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC), i: 0},
+		{in: "1306031020", out: time.Date(2013, time.June, 3, 10, 20, 0, 0, time.UTC), i: 1},
+		{in: "06/13/13", out: time.Date(2013, time.June, 13, 0, 0, 0, 0, time.UTC), i: 2},
+		{in: "13/06/13", out: time.Date(2013, time.June, 13, 0, 0, 0, 0, time.UTC), i: 3},
+		{in: "06/06/13", out: time.Date(2013, time.June, 6, 0, 0, 0, 0, time.UTC), i: 2},
+		{in: "06/03/13", fail: true},
+		{in: "2013-06", out: time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC), i: 5},
+		{in: "xx", fail: true},
+	}
+	for _, tt := range tests {
+		r := Resolver{Select: tt.sel}
+		got, i, err := r.ParseAny(tt.in, layouts, ref)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("%s: got %v", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+		} else if !got.Equal(tt.out) || i != tt.i {
+			t.Errorf("%s: got %v %d, want %v %d", tt.in, got, i, tt.out, tt.i)
+		}
+	}
+
+	var amb *AmbiguousError
+	if _, _, err := ParseAny("06/03/13", layouts, ref); !errors.As(err, &amb) || len(amb.Times) != 2 {
+		t.Errorf("06/03/13: %v", err)
+	}
+}
+
+func TestParseAnySelect(t *testing.T) {
+	// consistent results: June (day is literal) and June 3
+	layouts := []Layout{MustCompile("YYYY-MM-03"), MustCompile("YYYY-MM-DD")}
+	late := time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC)
+	early := time.Date(2013, time.May, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		sel Select
+		rt  time.Time
+		i   int
+	}{
+		{SelectFirst, late, 0},
+		{SelectPrecise, late, 1},
+		{SelectNearest, late, 1},
+		{SelectNearest, early, 0},
+	}
+	for _, tt := range tests {
+		r := Resolver{Select: tt.sel}
+		_, i, err := r.ParseAny("2013-06-03", layouts, tt.rt)
+		if err != nil {
+			t.Errorf("%d: %v", tt.sel, err)
+		} else if i != tt.i {
+			t.Errorf("%d %v: layout %d, want %d", tt.sel, tt.rt, i, tt.i)
+		}
+	}
+
+	r := Resolver{Sentinels: []Sentinel{{Data: "0000-00-00"}, {Data: "9999-99-99", Time: Never}}}
+	if _, i, err := r.ParseAny("0000-00-00", layouts, late); err != errNullDate || i != -1 {
+		t.Errorf("null sentinel: %d %v", i, err)
+	}
+	if _, err := r.Parse(layouts[0], "0000-00-00", late); err != errNullDate {
+		t.Errorf("null sentinel with Parse: %v", err)
+	}
+	if got, i, err := r.ParseAny("9999-99-99", layouts, late); err != nil || i != -1 || !got.Equal(Never) {
+		t.Errorf("open-ended sentinel: %v %d %v", got, i, err)
+	}
+}
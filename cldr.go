@@ -0,0 +1,195 @@
+package yy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cldrLetter returns layout for run of n pattern letters c
+func cldrLetter(c byte, n int) (string, bool) {
+	num := func(f string) string {
+		// n digits, single letter is 1 or 2 digits
+		if n == 1 {
+			return f + "{1,2}"
+		}
+		return strings.Repeat(f, n)
+	}
+	switch c {
+	case 'y', 'Y', 'u':
+		switch n {
+		case 1:
+			return "Y{1,4}", true
+		case 2:
+			return "YY", true
+		case 3:
+			return "Y{3,4}", true
+		}
+		return "YYYY", true
+	case 'Q', 'q':
+		switch n {
+		case 1:
+			return "Q", true
+		case 2:
+			return "0Q", true
+		case 3:
+			return "\\QQ", true
+		}
+		return "Q(\\st|nd|rd|t\\h) quarter", true
+	case 'M', 'L':
+		if n <= 2 {
+			return num("M"), true
+		}
+		if n <= 4 {
+			return "N", true
+		}
+	case 'w':
+		return num("V"), true
+	case 'd':
+		if n <= 2 {
+			return num("D"), true
+		}
+	case 'D':
+		switch n {
+		case 1:
+			return "J{1,3}", true
+		case 2:
+			return "J{2,3}", true
+		case 3:
+			return "JJJ", true
+		}
+	case 'E':
+		if n <= 4 {
+			return "W", true
+		}
+	case 'e', 'c':
+		if n <= 2 {
+			return strings.Repeat("0", n-1) + "E", true
+		}
+		if n <= 4 {
+			return "W", true
+		}
+	case 'a':
+		return "A", true
+	case 'h':
+		if n <= 2 {
+			return num("K"), true
+		}
+	case 'H':
+		if n <= 2 {
+			return num("h"), true
+		}
+	case 'm':
+		if n <= 2 {
+			return num("m"), true
+		}
+	case 's':
+		if n <= 2 {
+			return num("s"), true
+		}
+	case 'S':
+		return fmt.Sprintf("f{%d}", n), true
+	case 'z', 'v', 'V', 'O':
+		return "L{1,}", true
+	case 'Z':
+		switch {
+		case n <= 3:
+			return "L{5}", true
+		case n == 4:
+			return "L{4,}", true
+		case n == 5:
+			return "(L{6}|L)", true
+		}
+	case 'X', 'x':
+		w := [...]int{1: 3, 2: 5, 3: 6, 4: 7, 5: 9}
+		if n <= 5 {
+			if c == 'X' {
+				return fmt.Sprintf("(L{%d}|L)", w[n]), true
+			}
+			return fmt.Sprintf("L{%d}", w[n]), true
+		}
+	}
+	return "", false
+}
+
+// CompileCLDR compiles CLDR date pattern, as used in ICU and Java
+// ("yy-MM-dd", "dd.MM.", "MMM d", "yyDDD", "YYYY-'W'ww-e", "QQQ yy"), to Layout.
+//
+// Pattern letters are:
+//
+//	y Y u       year, y is 1 to 4 digits, yy is 2 digits, yyyy is 4 digits
+//	Q q         quarter: 1, 01, Q1, 1st quarter
+//	M L         month: 1, 01, month name (MMM and MMMM)
+//	w           ISO 8601 week of year, with Y it is ISO week-numbering year
+//	d D         day of month, day of year
+//	E e c       weekday: weekday name, e and c are weekday number (Monday is 1)
+//	a           AM or PM
+//	h H         hour 1..12, hour 0..23
+//	m s S       minute, second, fraction of second
+//	z v V O     timezone name
+//	Z X x       UTC offset, X accepts also "Z"
+//
+// Number of letters is width of field, single letter is field of 1 or 2 digits.
+// Text in single quotes is literal, two single quotes are single quote,
+// ASCII letters out of quotes are pattern letters, other chars are literals.
+//
+// Truncated years are resolved to nearest date, or as Resolver.YearWindow,
+// when it is set (80 is the rule of ICU and Java).
+// Names are full or abbreviated, case insensitive, and from locale of Resolver.
+func CompileCLDR(pattern string) (Layout, error) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			// quoted literal
+			j := i + 1
+			if j < len(pattern) && pattern[j] == '\'' {
+				b.WriteByte('\'')
+				i += 2
+				continue
+			}
+			for {
+				k := strings.IndexByte(pattern[j:], '\'')
+				if k < 0 {
+					return Layout{}, fmt.Errorf("yy: CLDR pattern %q: unterminated quote", pattern)
+				}
+				b.WriteString(layoutEscape(pattern[j : j+k]))
+				j += k + 1
+				if j < len(pattern) && pattern[j] == '\'' {
+					// '' in quoted text
+					b.WriteByte('\'')
+					j++
+					continue
+				}
+				break
+			}
+			i = j
+
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			n := 1
+			for i+n < len(pattern) && pattern[i+n] == c {
+				n++
+			}
+			l, ok := cldrLetter(c, n)
+			if !ok {
+				return Layout{}, fmt.Errorf("yy: CLDR pattern %q: unsupported %s", pattern, pattern[i:i+n])
+			}
+			b.WriteString(l)
+			i += n
+
+		default:
+			j := i + 1
+			for j < len(pattern) && pattern[j] != '\'' && !(pattern[j] >= 'a' && pattern[j] <= 'z' || pattern[j] >= 'A' && pattern[j] <= 'Z') {
+				j++
+			}
+			b.WriteString(layoutEscape(pattern[i:j]))
+			i = j
+		}
+	}
+	l, err := Compile(b.String())
+	if err != nil {
+		return Layout{}, err
+	}
+	l.src = pattern
+	return l, nil
+}
@@ -0,0 +1,96 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCLDR(t *testing.T) {
+	tests := []struct {
+		pattern, in string
+		out         time.Time
+	}{
+		{"yy-MM-dd", "13-06-03", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"yy-MM-dd", "65-06-03", time.Date(1965, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"dd.MM.", "03.06.", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"MMM d", "Dec 31", time.Date(2012, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{"MMMM d, y", "June 3, 2013", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"d/M/y", "3/6/13", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"yyDDD", "13154", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"YYYY-'W'ww-e", "2013-W01-1", time.Date(2012, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{"QQQ yy", "Q2 13", time.Date(2013, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"QQQQ y", "3rd quarter 2013", time.Date(2013, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{"QQ/MM/yyyy", "02/06/2013", time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"EEE, d MMM yyyy HH:mm:ss Z", "Mon, 3 Jun 2013 10:20:30 +0000", time.Date(2013, time.June, 3, 10, 20, 30, 0, time.UTC)},
+		{"h:mm a", "3:04 PM", time.Date(2013, time.June, 10, 15, 4, 0, 0, time.UTC)},
+		{"HH 'o''clock'", "10 o'clock", time.Date(2013, time.June, 10, 10, 0, 0, 0, time.UTC)},
+		{"''HH''", "'10'", time.Date(2013, time.June, 10, 10, 0, 0, 0, time.UTC)},
+		{"HH:mm:ss.SSSXXX", "10:20:30.250Z", time.Date(2013, time.June, 10, 10, 20, 30, 250000000, time.UTC)},
+		{"HH:mm:ss.SSSXXX", "10:20:30.250+02:00", time.Date(2013, time.June, 11, 8, 20, 30, 250000000, time.UTC)},
+	}
+
+	var r Resolver
+	for _, tt := range tests {
+		l, err := CompileCLDR(tt.pattern)
+		if err != nil {
+			t.Errorf("%s: %v", tt.pattern, err)
+			continue
+		}
+		got, err := r.Parse(l, tt.in, ref)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.pattern, tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s %s: got %v, want %v", tt.pattern, tt.in, got, tt.out)
+		}
+	}
+
+	for _, tt := range []struct{ pattern, in string }{
+		{"QQ/MM/yyyy", "01/06/2013"},
+		{"QQQ yy", "Q5 13"},
+	} {
+		l, err := CompileCLDR(tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := r.Parse(l, tt.in, ref); err == nil {
+			t.Errorf("%s %s: got %v", tt.pattern, tt.in, got)
+		}
+	}
+
+	for _, p := range []string{"MMMMM", "yy 'abc", "G y", "ddd", "k:mm"} {
+		if _, err := CompileCLDR(p); err == nil {
+			t.Errorf("%s: no error", p)
+		}
+	}
+}
+
+func TestYearWindow(t *testing.T) {
+	l, err := CompileCLDR("yy-MM-dd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in  string
+		out time.Time
+	}{
+		{"32-06-03", time.Date(2032, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"33-06-03", time.Date(1933, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"65-06-03", time.Date(1965, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"13-06-03", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	r := Resolver{YearWindow: 80}
+	for _, tt := range tests {
+		got, err := r.Parse(l, tt.in, ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+	}
+
+	// 4 digit years are not affected
+	got, err := r.FromFormat([]byte("2062-06-03"), []byte("YYYY-MM-DD"), ref)
+	if err != nil || got.Year() != 2062 {
+		t.Errorf("got %v, %v", got, err)
+	}
+}
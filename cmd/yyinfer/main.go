@@ -0,0 +1,72 @@
+// Command yyinfer proposes yy layouts for sample dates, one per line, read from files or stdin.
+//
+// Usage:
+//
+//	yyinfer [-ref 2006-01-02] [-n 5] [file ...]
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/djadala/yy"
+)
+
+func main() {
+	ref := flag.String("ref", "", "reference date, 2006-01-02 (default today)")
+	n := flag.Int("n", 5, "number of candidates")
+	flag.Parse()
+
+	var rt time.Time
+	if *ref != "" {
+		var err error
+		if rt, err = time.Parse("2006-01-02", *ref); err != nil {
+			fmt.Fprintln(os.Stderr, "yyinfer:", err)
+			os.Exit(2)
+		}
+	}
+
+	var samples []string
+	read := func(r io.Reader) {
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			// leading spaces are kept, they may be padding of field
+			if l := s.Text(); strings.TrimSpace(l) != "" {
+				samples = append(samples, l)
+			}
+		}
+		if err := s.Err(); err != nil {
+			fmt.Fprintln(os.Stderr, "yyinfer:", err)
+			os.Exit(1)
+		}
+	}
+	if flag.NArg() == 0 {
+		read(os.Stdin)
+	}
+	for _, name := range flag.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "yyinfer:", err)
+			os.Exit(1)
+		}
+		read(f)
+		f.Close()
+	}
+
+	c := yy.Infer(samples, rt)
+	if len(c) == 0 {
+		fmt.Fprintln(os.Stderr, "yyinfer: no layout found")
+		os.Exit(1)
+	}
+	if len(c) > *n {
+		c = c[:*n]
+	}
+	for _, x := range c {
+		fmt.Printf("%.3f\t%d/%d\t%s\n", x.Score, x.Parsed, len(samples), x.Layout)
+	}
+}
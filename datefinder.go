@@ -178,16 +178,91 @@ func (y *monthFind) gen(i int) (Tm, bool) {
 	return ret, isValidM(&ret) // валидна е когото всичко освен месеца и годината след нормализирне съвпаднат
 }
 
+// filter for dates with weekday wd
+type weekdayFind struct {
+	dateFinder
+	wd time.Weekday
+}
+
+func (w *weekdayFind) gen(i int) (Tm, bool) {
+	ret, valid := w.dateFinder.gen(i)
+	return ret, valid && ret.Date().Weekday() == w.wd
+}
+
+// struct for finding year of week date
+type weekFind struct {
+	yearFind
+	week Week
+	wd   int // weekday, -1 is first day of week
+}
+
+func newW(scale, yhi, y int, week Week, wd, h, m, s, f int, l *time.Location) *weekFind {
+	return &weekFind{
+		yearFind: *newY(scale, yhi, y, 1, 1, h, m, s, f, l),
+		week:     week,
+		wd:       wd,
+	}
+}
+
+func (w *weekFind) gen(i int) (Tm, bool) {
+	ret := w.get(i)
+	y, n := ret.Year, w.week.Get()
+	jan1 := time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// day of year (from 0) of first day of week 1, and offset of weekday in week
+	var first, off int
+	switch w.week.Kind {
+	case WeekISO:
+		jan4 := (int(jan1.Weekday()) + 3) % 7 // weekday of January 4
+		first = 3 - (jan4+6)%7
+		off = (w.wd + 6) % 7
+	case WeekSunday:
+		first = (7 - int(jan1.Weekday())) % 7
+		off = w.wd
+	case WeekMonday:
+		first = (8 - int(jan1.Weekday())) % 7
+		off = (w.wd + 6) % 7
+	}
+	if w.wd < 0 {
+		off = 0
+	}
+	d := jan1.AddDate(0, 0, first+(n-1)*7+off)
+	if w.wd < 0 && d.Before(jan1) {
+		// week 0 starts with January 1
+		d = jan1
+	}
+	ret.Year, ret.Month, ret.Day = d.Date()
+	valid := isValid(&ret)
+
+	switch w.week.Kind {
+	case WeekISO:
+		iy, iw := d.ISOWeek()
+		valid = valid && iy == y && iw == n
+	default:
+		valid = valid && d.Year() == y && n >= 0 && n <= 53
+	}
+	return ret, valid
+}
+
 var (
 	_ dateFinder = &yearFind{}
 	_ dateFinder = &yearFindJulian{}
 	_ dateFinder = &monthFind{}
+	_ dateFinder = &weekdayFind{}
+	_ dateFinder = &weekFind{}
 )
 
 ///////////////////////////////////////////////////////////
 
 // find closest date to ref from generated dates 0 +1 -1 +2 -2 +3 -3 ......
+// fewer than 3 valid dates are invalid
 func nearDateFind(ref time.Time, v dateFinder) (time.Time, error) {
+	return nearDateFindN(ref, v, 9, false)
+}
+
+// find closest date to ref from generated dates 0 +1 -1 ... +(n-1) -(n-1),
+// when few is set, also from 1 or 2 valid dates (filtered dates are rare)
+func nearDateFindN(ref time.Time, v dateFinder, n int, few bool) (time.Time, error) {
 
 	all := make([]time.Time, 0, 4)
 
@@ -195,7 +270,7 @@ func nearDateFind(ref time.Time, v dateFinder) (time.Time, error) {
 		all = append(all, t.Date())
 	}
 
-	for i := 1; i < 9; i++ {
+	for i := 1; i < n; i++ {
 		if t, valid := v.gen(i); valid {
 			all = append(all, t.Date())
 		}
@@ -207,5 +282,12 @@ func nearDateFind(ref time.Time, v dateFinder) (time.Time, error) {
 			return nearDate(ref, all[0], all[1], all[2]), nil
 		}
 	}
+	switch {
+	case !few:
+	case len(all) == 1:
+		return all[0], nil
+	case len(all) == 2:
+		return nearDate(ref, all[0], all[1], all[1]), nil
+	}
 	return time.Time{}, errInvalidDate
 }
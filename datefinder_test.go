@@ -0,0 +1,37 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+// sparseFind generates valid years only at given steps
+type sparseFind struct {
+	steps map[int]bool
+}
+
+func (v sparseFind) gen(i int) (Tm, bool) {
+	return Tm{Year: 2013 + i, Month: time.June, Day: 10, Loc: time.UTC}, v.steps[i]
+}
+
+func TestNearDateFindFew(t *testing.T) {
+	two := sparseFind{map[int]bool{0: true, 4: true}}
+
+	// date finders of fields need 3 valid dates
+	if got, err := nearDateFind(ref, two); err != errInvalidDate {
+		t.Errorf("got %v, %v, want %v", got, err, errInvalidDate)
+	}
+
+	got, err := nearDateFindN(ref, two, 9, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2013, 6, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	three := sparseFind{map[int]bool{0: true, 4: true, -8: true}}
+	if _, err := nearDateFind(ref, three); err != nil {
+		t.Error(err)
+	}
+}
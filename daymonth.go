@@ -0,0 +1,74 @@
+package yy
+
+import (
+	"errors"
+	"time"
+)
+
+var errMixedOrder = errors.New("mixed order of day and month")
+
+// DayMonth is policy for day and month, which can be swapped ("03/04" is March 4 or April 3)
+type DayMonth int
+
+// Day and month policies
+const (
+	DayMonthFixed   DayMonth = iota // day and month are as in data
+	DayMonthNearest                 // reading nearest to reference time, when both are valid
+	DayMonthLayout                  // reading as in data, swapped reading only when it is only valid one
+	DayMonthSwapped                 // swapped reading, reading as in data only when it is only valid one
+)
+
+// dayMonth resolves date with day and month swapped, and chooses
+// from it and t (resolved p, err is error of resolution) by policy r.DayMonth.
+// When both readings are valid and different, policy DayMonthNearest reports warning.
+func (r *Resolver) dayMonth(rt time.Time, p *IDate, t time.Time, err error) (time.Time, *Warning, error) {
+	if r.DayMonth == DayMonthFixed || !p.D.Present() || !p.Mo.Present() || p.D.Get() > 12 || p.D.Get() == p.Mo.Get() {
+		return t, nil, err
+	}
+	q := *p
+	q.D, q.Mo = p.Mo, p.D
+	s, serr := r.convert(r.ref(rt, &q), &q)
+	switch {
+	case serr != nil:
+		return t, nil, err
+	case err != nil:
+		return s, nil, nil
+	case r.DayMonth == DayMonthLayout:
+		return t, nil, nil
+	case r.DayMonth == DayMonthSwapped:
+		return s, nil, nil
+	}
+
+	if abs(int64(s.Sub(rt))) < abs(int64(t.Sub(rt))) {
+		t, s = s, t
+	}
+	y, m, d := t.Date()
+	return t, &Warning{Year: y, Month: m, Day: d, Date: t, Other: s}, nil
+}
+
+// DayMonthOrder returns order of day and month in batch of data with layout l:
+// DayMonthLayout if in some data day is over 12, DayMonthSwapped if month is over 12,
+// and DayMonthNearest if order can not be detected.
+// Error is returned if batch have both orders.
+// Data, which can not be parsed, is ignored.
+func (r *Resolver) DayMonthOrder(l Layout, data []string) (DayMonth, error) {
+	order := DayMonthNearest
+	for _, s := range data {
+		p, err := l.parse(s, r)
+		if err != nil || !p.D.Present() || !p.Mo.Present() {
+			continue
+		}
+		o := order
+		switch {
+		case p.D.Get() > 12:
+			o = DayMonthLayout
+		case p.Mo.Get() > 12:
+			o = DayMonthSwapped
+		}
+		if order != DayMonthNearest && o != order {
+			return DayMonthNearest, errMixedOrder
+		}
+		order = o
+	}
+	return order, nil
+}
@@ -0,0 +1,80 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayMonth(t *testing.T) {
+	l := MustCompile("DD/MM/YYYY")
+	tests := []struct {
+		in     string
+		policy DayMonth
+		out    time.Time
+		warn   bool
+	}{
+		{"03/04/2013", DayMonthFixed, time.Date(2013, time.April, 3, 0, 0, 0, 0, time.UTC), false},
+		{"03/04/2013", DayMonthLayout, time.Date(2013, time.April, 3, 0, 0, 0, 0, time.UTC), false},
+		{"03/04/2013", DayMonthSwapped, time.Date(2013, time.March, 4, 0, 0, 0, 0, time.UTC), false},
+		{"03/04/2013", DayMonthNearest, time.Date(2013, time.April, 3, 0, 0, 0, 0, time.UTC), true},
+		{"06/07/2013", DayMonthNearest, time.Date(2013, time.June, 7, 0, 0, 0, 0, time.UTC), true},
+		{"12/13/2013", DayMonthLayout, time.Date(2013, time.December, 13, 0, 0, 0, 0, time.UTC), false},
+		{"13/12/2013", DayMonthSwapped, time.Date(2013, time.December, 13, 0, 0, 0, 0, time.UTC), false},
+		{"12/13/2013", DayMonthNearest, time.Date(2013, time.December, 13, 0, 0, 0, 0, time.UTC), false},
+		{"05/05/2013", DayMonthNearest, time.Date(2013, time.May, 5, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		r := Resolver{DayMonth: tt.policy}
+		p, err := l.parse(tt.in, &r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, warn, err := r.ConvertWarn(ref, &p)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+		if (len(warn) > 0) != tt.warn {
+			t.Errorf("%s: warnings %v", tt.in, warn)
+		}
+	}
+
+	r := Resolver{DayMonth: DayMonthNearest}
+	p, _ := l.parse("03/04/2013", &r)
+	_, warn, _ := r.ConvertWarn(ref, &p)
+	if s := warn[0].String(); s != "ambiguous day and month, 2013-04-03 chosen over 2013-03-04" {
+		t.Errorf("warning %q", s)
+	}
+
+	r.DayMonth = DayMonthFixed
+	p, _ = l.parse("12/13/2013", &r)
+	if _, err := r.Convert(ref, &p); err == nil {
+		t.Errorf("12/13/2013: fixed order resolved")
+	}
+}
+
+func TestDayMonthOrder(t *testing.T) {
+	l := MustCompile("DD/MM/YYYY")
+	tests := []struct {
+		in    []string
+		order DayMonth
+		err   bool
+	}{
+		{[]string{"01/02/2013", "03/04/2013"}, DayMonthNearest, false},
+		{[]string{"01/02/2013", "25/04/2013", "bad"}, DayMonthLayout, false},
+		{[]string{"04/25/2013", "01/02/2013"}, DayMonthSwapped, false},
+		{[]string{"04/25/2013", "25/04/2013"}, DayMonthNearest, true},
+	}
+
+	for _, tt := range tests {
+		var r Resolver
+		order, err := r.DayMonthOrder(l, tt.in)
+		if (err != nil) != tt.err || order != tt.order {
+			t.Errorf("%v: got %v %v, want %v", tt.in, order, err, tt.order)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package yy_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/djadala/yy"
+)
+
+func ExampleFormat() {
+	var ref = time.Date(2013, time.June, 10, 23, 1, 2, 3, time.UTC)
+
+	fmt.Printf("%s\n", yy.Format(ref, []byte("DDNNNYY")))
+	fmt.Printf("%s\n", yy.LookupLocale("bg").Format(ref, []byte("WWWW, DD NNNN YYYY")))
+
+	ext := yy.Resolver{FormatExt: true}
+	r, err := ext.FromFormat([]byte("Tue 11 Jun"), []byte("WWW DD NNN"), ref)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(r)
+	// Output: 10Jun13
+	// понеделник, 10 юни 2013
+	// 2013-06-11 00:00:00 +0000 UTC
+}
@@ -0,0 +1,130 @@
+package yy
+
+import (
+	"time"
+)
+
+// Format returns textual representation of t according to format,
+// as accepted by FromFormat. Month and weekday names are English.
+func Format(t time.Time, format []byte) []byte {
+	return English.Format(t, format)
+}
+
+// months in roman numerals
+var romanMonths = [12]string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+
+// roman matches longest month in roman numerals at start of s, case insensitive,
+// returns month and length of numeral
+func roman(s []byte) (int, int, bool) {
+	v, max := 0, 0
+	for i, r := range romanMonths {
+		if n, ok := prefixFold(s, r); ok && n > max {
+			v, max = i+1, n
+		}
+	}
+	return v, max, max > 0
+}
+
+// appendInt appends v, zero padded to width digits, higher digits are dropped,
+// negative v is preceded by '-'
+func appendInt(b []byte, v, width int) []byte {
+	if v < 0 {
+		b = append(b, '-')
+		v = -v
+	}
+	for i := 0; i < width; i++ {
+		b = append(b, '0')
+	}
+	for i := len(b) - 1; i >= len(b)-width; i-- {
+		b[i] = byte('0' + v%10)
+		v /= 10
+	}
+	return b
+}
+
+// appendZone appends timezone for run of n 'L' chars:
+// 'z' or 'l' for UTC and Local when n = 1, offset +hhmm when n = 5,
+// offset +hh:mm when n = 6, otherwise name of location (or +hh:mm, if location have no name)
+func appendZone(b []byte, t time.Time, n int) []byte {
+	l := t.Location()
+	switch {
+	case n == 1 && l == time.UTC:
+		return append(b, 'z')
+	case n == 1 && l == time.Local:
+		return append(b, 'l')
+	case n != 5 && n != 6 && l.String() != "":
+		return append(b, l.String()...)
+	}
+	_, off := t.Zone()
+	sign := byte('+')
+	if off < 0 {
+		sign = '-'
+		off = -off
+	}
+	b = appendInt(append(b, sign), off/3600, 2)
+	if n != 5 {
+		b = append(b, ':')
+	}
+	return appendInt(b, off/60%60, 2)
+}
+
+// Format returns textual representation of t according to format,
+// as accepted by FromFormat, with month and weekday names from l.
+//
+// Numbers are zero padded to length of run of chars in format, years are truncated
+// to that number of digits, fraction to that number of leading digits.
+// Runs of 'N' and 'W' up to 3 chars long are abbreviated names, longer runs full names.
+// Run of 'I' is month in upper case roman numerals.
+// For 'L' see appendZone. Chars 'R' and 'U', and all other chars are copied as is.
+func (l *Locale) Format(t time.Time, format []byte) []byte {
+	b := make([]byte, 0, len(format)+8)
+	for i := 0; i < len(format); {
+		c := format[i]
+		n := 1
+		for i+n < len(format) && format[i+n] == c {
+			n++
+		}
+		switch c {
+		case 'Y':
+			b = appendInt(b, t.Year(), n)
+		case 'M':
+			b = appendInt(b, int(t.Month()), n)
+		case 'D':
+			b = appendInt(b, t.Day(), n)
+		case 'J':
+			b = appendInt(b, t.YearDay(), n)
+		case 'h':
+			b = appendInt(b, t.Hour(), n)
+		case 'm':
+			b = appendInt(b, t.Minute(), n)
+		case 's':
+			b = appendInt(b, t.Second(), n)
+		case 'f':
+			f := appendInt(nil, t.Nanosecond(), 9)
+			for len(f) < n {
+				f = append(f, '0')
+			}
+			b = append(b, f[:n]...)
+		case 'L':
+			b = appendZone(b, t, n)
+		case 'N':
+			if n <= 3 {
+				b = append(b, l.ShortMonths[t.Month()-1]...)
+			} else {
+				b = append(b, l.Months[t.Month()-1]...)
+			}
+		case 'I':
+			b = append(b, romanMonths[t.Month()-1]...)
+		case 'W':
+			if n <= 3 {
+				b = append(b, l.ShortWeekdays[t.Weekday()]...)
+			} else {
+				b = append(b, l.Weekdays[t.Weekday()]...)
+			}
+		default:
+			b = append(b, format[i:i+n]...)
+		}
+		i += n
+	}
+	return b
+}
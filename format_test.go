@@ -0,0 +1,178 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromFormatNames(t *testing.T) {
+	d := func(y int, m time.Month, dd int) time.Time { return time.Date(y, m, dd, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		in, fmt, lang string
+		out           time.Time
+	}{
+		{"10JUN13", "DDNNNYY", "", d(2013, 6, 10)},
+		{"Jun 10", "NNN DD", "", d(2013, 6, 10)},
+		{"June 10", "NNN DD", "", d(2013, 6, 10)},
+		{"10 september", "DD NNN", "", d(2013, 9, 10)},
+		{"10 юни 2013", "DD NNN YYYY", "bg", d(2013, 6, 10)},
+		{"10 ЮНИ 2013", "DD NNN YYYY", "bg", d(2013, 6, 10)},
+		{"Mo 10.06.", "WW DD.MM.", "de", d(2013, 6, 10)},
+		{"Fr 10.06.", "WW DD.MM.", "de", d(2011, 6, 10)},
+		{"mardi 11 juin", "WWWW DD NNNN", "fr", d(2013, 6, 11)},
+		{"15 de marzo", "DD de NNN", "es", d(2013, 3, 15)},
+		{"Fri", "WWW", "", d(2013, 6, 7)},
+		{"Thursday", "WWW", "", d(2013, 6, 13)},
+		{"Sat 29 Feb", "WWW DD NNN", "", d(2020, 2, 29)},
+		{"10.VI.2013", "DD.I.YYYY", "", d(2013, 6, 10)},
+		{"10.vi", "DD.II", "", d(2013, 6, 10)},
+		{"10.XII.2013", "DD.I.YYYY", "", d(2013, 12, 10)},
+		{"10.XI.13", "DD.I.YY", "", d(2013, 11, 10)},
+		{"1.VIII.2013", "D.I.YYYY", "", d(2013, 8, 1)},
+		{"30.iv", "DD.I", "", d(2013, 4, 30)},
+		{"IX/13", "I/YY", "", d(2013, 9, 1)},
+	}
+	for _, tt := range tests {
+		r := Resolver{Locale: LookupLocale(tt.lang), FormatExt: true}
+		got, err := r.FromFormat([]byte(tt.in), []byte(tt.fmt), ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+	}
+
+	for _, tt := range [][2]string{
+		{"Tue 10 Jun 2013", "WWW DD NNN YYYY"},
+		{"10 Foo 2013", "DD NNN YYYY"},
+		{"10 Jun", "DD NNN YYYY"},
+		{"10.XIII.2013", "DD.I.YYYY"},
+		{"10.M.2013", "DD.I.YYYY"},
+		{"31.IV", "DD.I"},
+	} {
+		r := Resolver{FormatExt: true}
+		if got, err := r.FromFormat([]byte(tt[0]), []byte(tt[1]), ref); err == nil {
+			t.Errorf("%s: expected error, got %v", tt[0], got)
+		}
+	}
+
+	// without FormatExt name letters are ignored, as in earlier formats
+	got, err := FromFormat([]byte("10 Jun 13"), []byte("DD NNN YY"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := d(2013, 6, 10); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	got, err = FromFormat([]byte("Now 10"), []byte("Now DD"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := d(2013, 6, 10); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tm := time.Date(2013, time.June, 10, 23, 1, 2, 123456789, time.UTC)
+	tests := []struct {
+		fmt, lang, out string
+	}{
+		{"YYYY-MM-DD hh:mm:ss.ffff", "", "2013-06-10 23:01:02.1234"},
+		{"YY-JJJ", "", "13-161"},
+		{"DDNNNYY", "", "10Jun13"},
+		{"WWWW DD NNNN", "", "Monday 10 June"},
+		{"WW DD.MM.", "de", "Mo 10.06."},
+		{"DD NNNN YYYY", "bg", "10 юни 2013"},
+		{"DD.I.YYYY", "", "10.VI.2013"},
+		{"hh:mm L", "", "23:01 z"},
+		{"hh:mm LLLLL", "", "23:01 +0000"},
+		{"hh:mm LLLLLL", "", "23:01 +00:00"},
+		{"RRR", "", "RRR"},
+	}
+	for _, tt := range tests {
+		l := English
+		if tt.lang != "" {
+			l = LookupLocale(tt.lang)
+		}
+		if got := string(l.Format(tm, []byte(tt.fmt))); got != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.fmt, got, tt.out)
+		}
+	}
+
+	// round trip
+	plus3 := time.FixedZone("", 3*3600)
+	tm = tm.In(plus3)
+	for _, f := range []string{"YYYY-MM-DD hh:mm:ss.fffffffff LLLLLL", "WWW DD NNN YYYY hh:mm:ss.fffffffff LLLLL"} {
+		s := Format(tm, []byte(f))
+		got, err := FromFormat(s, []byte(f), ref)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+		} else if !got.Equal(tm) {
+			t.Errorf("%s: got %v, want %v", s, got, tm)
+		}
+	}
+}
+
+func TestFromFormatRunes(t *testing.T) {
+	out := time.Date(2013, 6, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in, fmt, lang string
+	}{
+		{"13年6月10日", "YY年M月DD日", ""},
+		{"２０１３年０６月１０日", "YYYY年MM月DD日", ""},
+		{"١٠/٠٦/٢٠١٣", "DD/MM/YYYY", ""},
+		{"۱۰/۰۶/۱۳", "DD/MM/YY", ""},
+		{"१०-०६-२०१३", "DD-MM-YYYY", ""},
+		{"10 юни 2013", "DD NNN YYYY", "bg"},
+		{"пн, 10.VI", "WW, DD.I", "bg"},
+		{"→10←06", "→DD←MM", ""},
+	}
+	for _, tt := range tests {
+		r := Resolver{Locale: LookupLocale(tt.lang), FormatExt: true}
+		got, err := r.FromFormat([]byte(tt.in), []byte(tt.fmt), ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, out)
+		}
+	}
+
+	// fraction & year digits are counted in runes
+	got, err := FromFormat([]byte("٩٩-١٢٣ ٠٠:٠٠:٠٠.٥"), []byte("YY-JJJ hh:mm:ss.f"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(1999, 5, 3, 0, 0, 0, 500000000, time.UTC); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var i Int
+	if err := i.Set([]byte("1a")); err == nil {
+		t.Error("expected error")
+	}
+	if err := i.Set([]byte("４２")); err != nil || i.Get() != 42 {
+		t.Errorf("got %d %v", i.Get(), err)
+	}
+}
+
+func TestAppendInt(t *testing.T) {
+	for _, tt := range []struct {
+		v, width int
+		out      string
+	}{
+		{2013, 4, "2013"},
+		{2013, 2, "13"},
+		{7, 3, "007"},
+		{-44, 4, "-0044"},
+		{-2013, 2, "-13"},
+	} {
+		if got := string(appendInt([]byte("x"), tt.v, tt.width)); got != "x"+tt.out {
+			t.Errorf("%d/%d: got %q, want %q", tt.v, tt.width, got, "x"+tt.out)
+		}
+	}
+}
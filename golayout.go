@@ -0,0 +1,150 @@
+package yy
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// goChunks are elements of Go reference layout and their layouts,
+// longer elements are first
+var goChunks = []struct {
+	std, layout string
+	word        bool // not element when followed by lower case letter ("Month")
+}{
+	{"January", "N", false},
+	{"Jan", "N", true},
+	{"Monday", "W", false},
+	{"Mon", "W", true},
+	{"MST", "L{3,}", false},
+	{"2006", "YYYY", false},
+	{"002", "JJJ", false},
+	{"__2", "J{1,3>}", false},
+	{"_2", "D{1,2>}", false},
+	{"01", "MM", false},
+	{"02", "DD", false},
+	{"03", "KK", false},
+	{"04", "mm", false},
+	{"05", "ss", false},
+	{"06", "YY", false},
+	{"15", "h{1,2}", false},
+	{"1", "M{1,2}", false},
+	{"2", "D{1,2}", false},
+	{"3", "K{1,2}", false},
+	{"4", "m{1,2}", false},
+	{"5", "s{1,2}", false},
+	{"PM", "A", false},
+	{"pm", "A", false},
+	{"-07:00:00", "L{9}", false},
+	{"-070000", "L{7}", false},
+	{"-07:00", "L{6}", false},
+	{"-0700", "L{5}", false},
+	{"-07", "L{3}", false},
+	{"Z07:00:00", "(L{9}|L)", false},
+	{"Z070000", "(L{7}|L)", false},
+	{"Z07:00", "(L{6}|L)", false},
+	{"Z0700", "(L{5}|L)", false},
+	{"Z07", "(L{3}|L)", false},
+}
+
+// goFrac returns length of fractional seconds (".000", ",999") at start of s, or 0,
+// and if fraction is optional (",999")
+func goFrac(s string) (int, bool) {
+	if len(s) < 2 || (s[0] != '.' && s[0] != ',') || (s[1] != '0' && s[1] != '9') {
+		return 0, false
+	}
+	n := 2
+	for n < len(s) && s[n] == s[1] {
+		n++
+	}
+	if n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		return 0, false
+	}
+	return n, s[1] == '9'
+}
+
+// layoutEscape returns s with field & syntax chars escaped
+func layoutEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if isLayoutField(r) || strings.ContainsRune("\\{}[]()|", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// CompileGo compiles Go reference layout, as in time.Parse ("2006-01-02", "Jan _2 15:04:05"),
+// to Layout.
+//
+// Elements of layout are as in time package, two digit year "06" is year with
+// 2 digits, resolved to nearest date, and missing year, month and day are taken
+// from reference time, as in Convert.
+// Seconds can be followed by fraction, even if layout does not have one.
+// Names of months, weekdays and AM/PM are case insensitive, full and abbreviated
+// names are accepted for both "Jan" and "January".
+//
+// As in time.Parse, weekday is not checked when layout has day ("Tue, 10 Jun 2013"
+// is June 10), and unknown zone abbreviations ("XYZ") are fabricated zones with
+// that name and zero offset. Without day, weekday resolves to nearest date with it.
+// String of layout is Go layout.
+func CompileGo(layout string) (Layout, error) {
+	var b strings.Builder
+	lit := 0 // start of literal text
+	for i := 0; i < len(layout); {
+		std, yl := "", ""
+		if n, opt := goFrac(layout[i:]); n > 0 {
+			std, yl = layout[i:i+n], "(.|,)f{"+strconv.Itoa(n-1)+"}"
+			if opt {
+				yl = "[(.|,)f{1,9}]"
+			}
+		} else if !strings.HasPrefix(layout[i:], "_2006") {
+			// _2006 is _ followed by year
+			for _, c := range goChunks {
+				if strings.HasPrefix(layout[i:], c.std) && !(c.word && startsLower(layout[i+len(c.std):])) {
+					std, yl = c.std, c.layout
+					break
+				}
+			}
+		}
+		if std == "" {
+			i++
+			continue
+		}
+		b.WriteString(layoutEscape(layout[lit:i]))
+		b.WriteString(yl)
+		i += len(std)
+		lit = i
+		if n, _ := goFrac(layout[i:]); (std == "05" || std == "5") && n == 0 {
+			// fraction after seconds
+			b.WriteString("[(.|,)f{1,9}]")
+		}
+	}
+	b.WriteString(layoutEscape(layout[lit:]))
+	l, err := Compile(b.String())
+	if err != nil {
+		return Layout{}, err
+	}
+	l.src = layout
+	l.goTime = true
+	return l, nil
+}
+
+// startsLower returns if s starts with lower case letter
+func startsLower(s string) bool {
+	return len(s) > 0 && s[0] >= 'a' && s[0] <= 'z'
+}
+
+// Parse parses value with Go reference layout (see CompileGo), like time.Parse,
+// but two digit and missing years, months and days are resolved nearest to
+// reference time ref, as in Convert.
+// Without timezone in value, time is in location of ref.
+func Parse(layout, value string, ref time.Time) (time.Time, error) {
+	l, err := CompileGo(layout)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var r Resolver
+	return r.Parse(l, value, ref)
+}
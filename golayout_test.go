@@ -0,0 +1,147 @@
+package yy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGoCompat(t *testing.T) {
+	// full layouts parse as in time package
+	tests := []struct {
+		layout, value string
+	}{
+		{time.RFC3339, "2013-06-10T23:01:02Z"},
+		{time.RFC3339, "2013-06-10T23:01:02+03:00"},
+		{time.RFC3339Nano, "2013-06-10T23:01:02.123456789-07:00"},
+		{time.RFC3339, "2013-06-10T23:01:02.5Z"},
+		{time.RFC1123Z, "Mon, 10 Jun 2013 23:01:02 +0200"},
+		{time.ANSIC, "Mon Jun  3 23:01:02 2013"},
+		{time.ANSIC, "Mon Jun 10 23:01:02 2013"},
+		{time.Kitchen + " 2006-01-02", "3:04PM 2013-06-10"},
+		{time.Kitchen + " 2006-01-02", "12:04AM 2013-06-10"},
+		{"2006-01-02 15:04:05,000", "2013-06-10 23:01:02,250"},
+		{"2006-01-02 15:04:05.999", "2013-06-10 23:01:02"},
+		{"2006.002", "2013.161"},
+		{"2006 __2", "2013  61"},
+		{"January 2, 2006", "June 3, 2013"},
+		{"Month: 01 _2006", "Month: 06 _2013"},
+	}
+	for _, tt := range tests {
+		want, err := time.Parse(tt.layout, tt.value)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.layout, tt.value, err)
+		}
+		got, err := Parse(tt.layout, tt.value, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.layout, tt.value, err)
+		} else if !got.Equal(want) {
+			t.Errorf("%s %s: got %v, want %v", tt.layout, tt.value, got, want)
+		}
+	}
+}
+
+func TestParseGoNearest(t *testing.T) {
+	tests := []struct {
+		layout, value string
+		out           time.Time
+	}{
+		{"06-01-02", "13-06-03", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"06-01-02", "62-06-01", time.Date(2062, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"06-01-02", "65-01-01", time.Date(1965, time.January, 1, 0, 0, 0, 0, time.UTC)}, // time.Parse: 2065
+		{"06-01-02", "70-01-01", time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"3:04pm", "12:04PM", time.Date(2013, time.June, 10, 12, 4, 0, 0, time.UTC)},
+		{"Jan _2 15:04", "Dec 31 23:59", time.Date(2012, time.December, 31, 23, 59, 0, 0, time.UTC)},
+		{"Jan _2 15:04", "Jun  3 10:00", time.Date(2013, time.June, 3, 10, 0, 0, 0, time.UTC)},
+		{"2 Jan", "29 Feb", time.Date(2012, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"002", "365", time.Date(2012, time.December, 30, 0, 0, 0, 0, time.UTC)},
+		{"15:04", "10:20", time.Date(2013, time.June, 10, 10, 20, 0, 0, time.UTC)},
+		{"Monday", "Tuesday", time.Date(2013, time.June, 11, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.layout, tt.value, ref)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.layout, tt.value, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s %s: got %v, want %v", tt.layout, tt.value, got, tt.out)
+		}
+	}
+
+	for _, tt := range []struct{ layout, value string }{
+		{"06-01-02", "13-02-30"},
+		{"3:04PM", "13:04PM"},
+		{"3:04PM", "0:04PM"},
+		{"15:04", "10:20:30"},
+		{"Jan 2", "Jux 2"},
+	} {
+		if got, err := Parse(tt.layout, tt.value, ref); err == nil {
+			t.Errorf("%s %s: got %v", tt.layout, tt.value, got)
+		}
+	}
+}
+
+func TestCompileGo(t *testing.T) {
+	tests := []struct {
+		layout, out string
+	}{
+		{"2006-01-02", "YYYY-MM-DD"},
+		{"Jan _2 15:04:05.000", "N D{1,2>} h{1,2}:mm:ss(.|,)f{3}"},
+		{"15:04:05 MST", "h{1,2}:mm:ss[(.|,)f{1,9}] L{3,}"},
+		{"Month 1", "\\Mont\\h M{1,2}"},
+		{"2006-01-02T15:04:05Z07:00", "YYYY-MM-DDTh{1,2}:mm:ss[(.|,)f{1,9}](L{6}|L)"},
+	}
+	for _, tt := range tests {
+		l, err := CompileGo(tt.layout)
+		if err != nil {
+			t.Errorf("%s: %v", tt.layout, err)
+			continue
+		}
+		if l.String() != tt.layout {
+			t.Errorf("%s: source %s", tt.layout, l)
+		}
+		if want := MustCompile(tt.out); !reflect.DeepEqual(l.seq, want.seq) {
+			t.Errorf("%s: compiled as %v, want %s", tt.layout, l.seq, tt.out)
+		}
+	}
+}
+
+func TestParseGoStandard(t *testing.T) {
+	layouts := []string{
+		time.ANSIC, time.UnixDate, time.RubyDate, time.RFC822, time.RFC822Z, time.RFC850,
+		time.RFC1123, time.RFC1123Z, time.RFC3339, time.RFC3339Nano,
+		"2006-01-02 15:04:05", "2006-01-02",
+	}
+	times := []time.Time{
+		time.Date(2013, time.June, 10, 23, 1, 2, 123456789, time.UTC),
+		time.Date(2013, time.January, 3, 4, 5, 6, 0, time.FixedZone("XYZ", 0)),
+		time.Date(2012, time.February, 29, 12, 0, 0, 5000, time.FixedZone("ABC", -7*3600-30*60)),
+	}
+	rt := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, layout := range layouts {
+		for _, tm := range times {
+			value := tm.Format(layout)
+			want, err := time.Parse(layout, value)
+			if err != nil {
+				t.Fatalf("%s %s: %v", layout, value, err)
+			}
+			got, err := Parse(layout, value, rt)
+			if err != nil {
+				t.Errorf("%s %s: %v", layout, value, err)
+				continue
+			}
+			gn, goff := got.Zone()
+			wn, woff := want.Zone()
+			if !got.Equal(want) || goff != woff || strings.Contains(layout, "MST") && gn != wn {
+				t.Errorf("%s %s: got %v, want %v", layout, value, got, want)
+			}
+		}
+	}
+
+	// weekday is not checked, as in time.Parse
+	value := "Tue, 10 Jun 2013 23:01:02 GMT"
+	want, _ := time.Parse(time.RFC1123, value)
+	if got, err := Parse(time.RFC1123, value, rt); err != nil || !got.Equal(want) {
+		t.Errorf("%s: got %v %v, want %v", value, got, err, want)
+	}
+}
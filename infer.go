@@ -0,0 +1,354 @@
+package yy
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Candidate is layout proposed by Infer
+type Candidate struct {
+	Layout Layout
+	Parsed int           // number of samples parsed and resolved
+	Mean   time.Duration // mean distance of resolved samples from reference
+	Score  float64       // 0..1, higher is better
+}
+
+// date orders tried by Infer, fields of time follow date
+var inferOrders = []string{"YMD", "DMY", "MDY", "YJ", ""}
+
+// token of sample: run of digits, run of letters or other char
+type token struct {
+	digits, letters bool
+	s               string
+}
+
+// tokens splits s to tokens
+func tokens(s string) []token {
+	var t []token
+	for i := 0; i < len(s); {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		run := func(f func(rune) bool) int {
+			j := i
+			for j < len(s) {
+				r, n := utf8.DecodeRuneInString(s[j:])
+				if !f(r) {
+					break
+				}
+				j += n
+			}
+			return j
+		}
+		switch {
+		case unicode.IsDigit(r):
+			j := run(unicode.IsDigit)
+			t = append(t, token{digits: true, s: s[i:j]})
+			i = j
+		case unicode.IsLetter(r):
+			j := run(unicode.IsLetter)
+			t = append(t, token{letters: true, s: s[i:j]})
+			i = j
+		default:
+			t = append(t, token{s: s[i : i+n]})
+			i += n
+		}
+	}
+	return t
+}
+
+// shape returns key of tokens, samples of same shape have same candidates
+func shape(t []token) string {
+	var b strings.Builder
+	for _, k := range t {
+		switch {
+		case k.digits:
+			b.WriteString("9" + strconv.Itoa(len(k.s)))
+		case k.letters:
+			b.WriteString("a" + strings.ToUpper(k.s))
+		default:
+			b.WriteString(k.s)
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// inferer generates candidate layouts for tokens of sample
+type inferer struct {
+	loc  *Locale
+	t    []token
+	emit func(string)
+}
+
+// fieldLayout returns layout of field c, when it is whole token of n digits
+func fieldLayout(c byte, n int) (string, bool) {
+	switch c {
+	case 'Y':
+		if n == 2 || n == 4 {
+			return strings.Repeat("Y", n), true
+		}
+	case 'J':
+		if n == 3 {
+			return "JJJ", true
+		}
+		if n < 3 {
+			return "J{1,3}", true
+		}
+	case 'f':
+		if n <= 9 {
+			return "f{1,9}", true
+		}
+	default:
+		if n == 2 {
+			return string([]byte{c, c}), true
+		}
+		if n == 1 {
+			return string(c) + "{1,2}", true
+		}
+	}
+	return "", false
+}
+
+// padLayout returns layout of field c, right justified with spaces to 2 chars
+func padLayout(c byte) (string, bool) {
+	if strings.IndexByte("MDhKms", c) < 0 {
+		return "", false
+	}
+	return string([]byte{c, c}) + "{>}", true
+}
+
+// compact calls f for each split of digits s to fixed width fields from start of q
+func compact(s, q, lay string, f func(lay, q string)) {
+	if s == "" {
+		f(lay, q)
+		return
+	}
+	if q == "" {
+		return
+	}
+	widths := []int{2}
+	switch q[0] {
+	case 'Y':
+		widths = []int{4, 2}
+	case 'J':
+		widths = []int{3}
+	case 'f':
+		widths = []int{len(s)}
+	}
+	for _, w := range widths {
+		if w <= len(s) && w <= 9 {
+			compact(s[w:], q[1:], lay+strings.Repeat(string(q[0]), w), f)
+		}
+	}
+}
+
+// loose returns if fields of split token are end of date and start of time,
+// with one date field ("YYhh" after "DD/MM/"), which is noise
+func loose(fields string) bool {
+	date := 0
+	for i := 0; i < len(fields); i++ {
+		if strings.IndexByte("YMDJ", fields[i]) >= 0 {
+			date++
+		}
+	}
+	return date == 1 && len(fields) > 1
+}
+
+// clock returns if time is started (or date without time is complete), q is remaining fields
+func clock(q string) bool {
+	return !strings.ContainsAny(q, "YMDJhK")
+}
+
+// gen generates layouts for tokens from i, with remaining fields q
+func (g *inferer) gen(i int, q, lay string, fields bool) {
+	if i == len(g.t) {
+		if fields {
+			g.emit(lay)
+		}
+		return
+	}
+	k := g.t[i]
+	switch {
+	case k.digits:
+		if q != "" && q[0] == 'f' && (i == 0 || g.t[i-1].s != "." && g.t[i-1].s != ",") {
+			// fraction only after decimal separator
+			q = q[1:]
+		}
+		if g.colon(i) {
+			// fields around ':' are time, missing date fields are skipped
+			n := strings.IndexAny(q, "hKms")
+			if n < 0 {
+				return
+			}
+			q = q[n:]
+		}
+		if q == "" {
+			return
+		}
+		if l, ok := fieldLayout(q[0], len(k.s)); ok {
+			g.gen(i+1, q[1:], lay+l, true)
+		}
+		if len(k.s) == 1 && i > 0 && g.t[i-1].s == " " && (i == 1 || g.t[i-2].s == " ") {
+			// space padded field ("Jun  9"), space before digit is padding
+			if l, ok := padLayout(q[0]); ok {
+				g.gen(i+1, q[1:], strings.TrimSuffix(lay, " ")+l, true)
+			}
+		}
+		if len(k.s) > 2 {
+			compact(k.s, q, "", func(l, rest string) {
+				if f := q[:len(q)-len(rest)]; len(f) > 1 && !loose(f) {
+					g.gen(i+1, rest, lay+l, true)
+				}
+			})
+		}
+
+	case k.letters:
+		if g.name(k.s, true) {
+			// month name is month in any order of date
+			if n := strings.IndexByte(q, 'M'); n >= 0 {
+				g.gen(i+1, q[:n]+q[n+1:], lay+"N", true)
+			}
+			return
+		}
+		if g.name(k.s, false) {
+			g.gen(i+1, q, lay+"W", true)
+			return
+		}
+		switch u := strings.ToUpper(k.s); {
+		case u == "AM" || u == "PM":
+			g.gen(i+1, q, lay+"A", fields)
+		case clock(q) && u != "T":
+			g.gen(i+1, q, lay+"L{1,}", fields)
+		default:
+			g.gen(i+1, q, lay+layoutEscape(k.s), fields)
+		}
+
+	default:
+		if (k.s == "+" || k.s == "-") && clock(q) && i+1 < len(g.t) && g.t[i+1].digits {
+			// UTC offset
+			switch n := len(g.t[i+1].s); {
+			case n == 4:
+				g.gen(i+2, "", lay+"L{5}", fields)
+				return
+			case n == 2 && i+3 < len(g.t) && g.t[i+2].s == ":" && len(g.t[i+3].s) == 2:
+				g.gen(i+4, "", lay+"L{6}", fields)
+				return
+			case n == 2:
+				g.gen(i+2, "", lay+"L{3}", fields)
+				return
+			}
+		}
+		g.gen(i+1, q, lay+layoutEscape(k.s), fields)
+	}
+}
+
+// colon returns if token i is next to ':'
+func (g *inferer) colon(i int) bool {
+	return i > 0 && g.t[i-1].s == ":" || i+1 < len(g.t) && g.t[i+1].s == ":"
+}
+
+// name returns if s is whole name of month (or weekday)
+func (g *inferer) name(s string, month bool) bool {
+	_, n, ok := g.loc.name([]byte(s), month)
+	return ok && n == len(s)
+}
+
+// Infer proposes layouts for sample of data, ranked by score.
+//
+// Candidates are generated from shapes of samples: runs of digits are assigned to fields
+// in date orders year-month-day, day-month-year, month-day-year and year-day of year,
+// followed by hour, minute, second and fraction (only in samples with ':' or AM/PM),
+// and are split to fields when needed
+// ("130610" is YYMMDD, DDMMYY or MMDDYY, but not split to one date field and time),
+// single digits after two spaces are space padded fields ("Jun  9" is N DD{>}),
+// letters are month and weekday names,
+// AM/PM or timezone after time, and UTC offsets are recognized after time.
+//
+// Score is fraction of samples, which parse and resolve with candidate, lowered
+// by mean distance of them from reference rt (score is halved by mean distance of 1 year).
+// Zero rt is current time. Candidates, which do not parse any sample, are not returned.
+func (r *Resolver) Infer(samples []string, rt time.Time) []Candidate {
+	if rt.IsZero() {
+		rt = time.Now()
+	}
+
+	seen := map[string]bool{}
+	var layouts []string
+	g := inferer{loc: r.locale()}
+	g.emit = func(l string) {
+		if !seen[l] {
+			seen[l] = true
+			layouts = append(layouts, l)
+		}
+	}
+	shapes := map[string]bool{}
+	for _, s := range samples {
+		g.t = tokens(s)
+		k := shape(g.t)
+		if shapes[k] {
+			continue
+		}
+		shapes[k] = true
+		hour, clock := "h", strings.Contains(s, ":")
+		for _, k := range g.t {
+			if u := strings.ToUpper(k.s); k.letters && (u == "AM" || u == "PM") {
+				hour, clock = "K", true
+			}
+		}
+		for _, o := range inferOrders {
+			// without ':' or AM/PM, digits are date
+			switch {
+			case clock:
+				g.gen(0, o+hour+"msf", "", false)
+			case o != "":
+				g.gen(0, o, "", false)
+			}
+		}
+	}
+
+	var c []Candidate
+	for _, s := range layouts {
+		l, err := Compile(s)
+		if err != nil {
+			continue
+		}
+		var sum float64 // distances in hours
+		n := 0
+		for _, d := range samples {
+			p, err := l.parse(d, r)
+			if err != nil {
+				continue
+			}
+			t, err := r.Convert(rt, &p)
+			if err != nil {
+				continue
+			}
+			sum += math.Abs(t.Sub(rt).Hours())
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		mean := sum / float64(n)
+		score := float64(n) / float64(len(samples)) / (1 + mean/(365.25*24))
+		d := time.Duration(math.MaxInt64)
+		if h := mean * float64(time.Hour); h < math.MaxInt64 {
+			d = time.Duration(h)
+		}
+		c = append(c, Candidate{Layout: l, Parsed: n, Mean: d, Score: score})
+	}
+	sort.SliceStable(c, func(i, j int) bool {
+		return c[i].Score > c[j].Score
+	})
+	return c
+}
+
+// Infer proposes layouts for sample of data, like Resolver.Infer
+func Infer(samples []string, ref time.Time) []Candidate {
+	var r Resolver
+	return r.Infer(samples, ref)
+}
@@ -0,0 +1,63 @@
+package yy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		in     []string
+		layout string
+	}{
+		{[]string{"2013-06-03", "2013-05-28", "2012-12-31"}, "YYYY-MM-DD"},
+		{[]string{"130603", "130528", "121231"}, "YYMMDD"},
+		{[]string{"030613", "280513", "311212"}, "DDMMYY"},
+		{[]string{"13154", "13003", "12366"}, "YYJJJ"},
+		{[]string{"3/6/2013", "28/5/2013", "31/12/2012"}, "D{1,2}/M{1,2}/YYYY"},
+		{[]string{"06/03/2013", "05/28/2013"}, "MM/DD/YYYY"},
+		{[]string{"2013-06-03T10:20:30.5+02:00", "2013-06-04T11:00:00.25+02:00"}, "YYYY-MM-DDThh:mm:ss.f{1,9}L{6}"},
+		{[]string{"Jun 3 10:20:30", "May 28 09:00:01"}, "N D{1,2} hh:mm:ss"},
+		{[]string{"10:20", "9:05"}, "h{1,2}:mm"},
+		{[]string{"Mon, 03 Jun 2013 10:20 PM"}, "W, DD N YYYY KK:mm A"},
+	}
+
+	for _, tt := range tests {
+		c := Infer(tt.in, ref)
+		if len(c) == 0 {
+			t.Errorf("%v: no candidates", tt.in)
+			continue
+		}
+		if s := c[0].Layout.String(); s != tt.layout {
+			t.Errorf("%v: got %s, want %s", tt.in, s, tt.layout)
+			for _, x := range c {
+				t.Logf("%s %d %v %.3f", x.Layout, x.Parsed, x.Mean, x.Score)
+			}
+		}
+		if c[0].Parsed != len(tt.in) {
+			t.Errorf("%v: parsed %d", tt.in, c[0].Parsed)
+		}
+	}
+}
+
+func TestInferSyslog(t *testing.T) {
+	in := []string{"Jun 10 23:01:02", "Jun  9 10:00:00"}
+	c := Infer(in, ref)
+	if len(c) == 0 {
+		t.Fatal("no candidates")
+	}
+	if s := c[0].Layout.String(); s != "N DD{>} hh:mm:ss" || c[0].Parsed != len(in) {
+		t.Errorf("got %s parsed %d, want N DD{>} hh:mm:ss", s, c[0].Parsed)
+	}
+
+	// month names are N in every date order, digits are not split to one date field and time
+	// without ':' or AM/PM digits are date only
+	for _, in := range [][]string{in, {"10/06/2013", "28/05/2013"}, {"Jun 10 2013"}, {"2013-06-10"}} {
+		for _, x := range Infer(in, ref) {
+			s := x.Layout.String()
+			if strings.Contains(s, "\\") || strings.Contains(s, "YYhh") || !strings.Contains(in[0], ":") && strings.ContainsAny(s, "hKms") {
+				t.Errorf("%v: noisy candidate %s", in, s)
+			}
+		}
+	}
+}
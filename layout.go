@@ -0,0 +1,530 @@
+package yy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var errNoMatch = errors.New("data does not match layout")
+
+// Layout is compiled layout, in tokenizing mode.
+//
+// Unlike formats of FromFormat, where every char in format is one char in date,
+// layout is sequence of fields and literals, fields are separated by literals
+// and can be of variable width, so one layout handles padded and unpadded data:
+//
+//	D{1,2}/M{1,2}/YY      matches "1/5/13" and "10/15/13"
+//	hh:mm:ss.f{1,9} L{1,} matches "10:20:30.5 Europe/Sofia"
+//
+// Field chars are as in FromFormat (Y,M,D,J,h,m,s,f,L,R,U,N,W,I), and
+//
+//	K   hour on 12-hour clock, 1..12
+//	A   AM or PM, case insensitive
+//	C   century, with 2 digit year CCYY is 4 digit year
+//	E   weekday number 1..7, Monday is 1, 0 and 7 are Sunday
+//	V   ISO 8601 week number 1..53, year is ISO week-numbering year
+//	X   week number 0..53, week 1 starts with first Sunday of year
+//	O   week number 0..53, week 1 starts with first Monday of year
+//	Q   quarter 1..4, without month it is first month of quarter
+//
+// Date can be year (or part of it), week and weekday (first day of week, if missing).
+//
+// Run of n same field chars is field of width n, single field char followed
+// by {min,max}, {min,} or {n} is field of width from min to max (or unbounded) chars.
+// Chars 'N', 'W', 'I' and 'A' (names & roman numerals) are always of width of matched name.
+//
+// Numeric fields (Y,M,D,J,h,K,m,s,f,C,E,V,X,O,Q) match Unicode decimal digits,
+// R matches sign, digits & letters, U letters, L any chars.
+// All other chars are literals, and must be present in data.
+// Field chars and '\', '{', '}', '[', ']', '(', ')', '|' are literals when escaped with '\'.
+//
+// Sections in brackets are optional, "(a|b|c)" are alternatives:
+//
+//	YYMMDD[hhmm[ss]]      matches "130610", "1306101530" and "130610153045"
+//	(YYYY|YY)-MM-DD       matches "2013-06-10" and "13-06-10"
+//
+// Optional sections are matched if present, alternatives are tried in order,
+// fields in sections that are not matched are not present in IDate.
+//
+// Fields can be justified and padded, in braces after field, alone or after width:
+//
+//	DD{>}      right justified day, padded with spaces: " 3"
+//	D{1,3>*}   day of 1 to 3 chars, right justified, padded with stars: "**3"
+//	L{6<_}     timezone, left justified, padded with underscores: "CET___"
+//
+// Padding on left of right justified field and on right of left justified field
+// is ignored, field consisting only of padding is blank, and is invalid
+// or absent (Resolver.BlankAbsent). Padding can not be digit.
+//
+// Variable width fields are matched greedily, but if rest of layout does not
+// match, shorter widths are tried, so "L{1,} hh" takes timezone until last space.
+// Width is counted in runes.
+type Layout struct {
+	src string
+	seq []node
+
+	// goTime parses as time.Parse: weekday is not checked with day,
+	// unknown zone abbreviations are fabricated zones with zero offset
+	goTime bool
+}
+
+type nodeKind int8
+
+const (
+	nLit nodeKind = iota
+	nField
+	nGroup
+)
+
+// node of compiled layout
+type node struct {
+	kind     nodeKind
+	lit      rune     // literal
+	c        byte     // field char
+	min, max int      // width of field, max < 0 is unbounded
+	just     byte     // justification '<' or '>', 0 is none
+	pad      rune     // padding of justified field
+	alts     [][]node // alternatives of group
+	opt      bool     // group is optional
+}
+
+// span of data matched by field
+type span struct {
+	n    *node
+	s, e int
+}
+
+// isLayoutField returns if c is field char in layout
+func isLayoutField(c rune) bool {
+	switch c {
+	case 'U', 'N', 'W', 'I', 'K', 'A', 'C', 'E', 'V', 'X', 'O', 'Q':
+		return true
+	}
+	return c < utf8.RuneSelf && isField(byte(c))
+}
+
+// isNameField returns if field is name or roman numeral
+func isNameField(c byte) bool {
+	return c == 'N' || c == 'W' || c == 'I' || c == 'A'
+}
+
+// Compile compiles layout
+func Compile(layout string) (Layout, error) {
+	c := compiler{src: layout}
+	seq, err := c.seq("")
+	if err != nil {
+		return Layout{}, err
+	}
+	return Layout{src: layout, seq: seq}, nil
+}
+
+// MustCompile is like Compile, but panics if layout can not be compiled
+func MustCompile(layout string) Layout {
+	l, err := Compile(layout)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// String returns source of layout
+func (l Layout) String() string {
+	return l.src
+}
+
+func layoutError(layout string, pos int, msg string) error {
+	return fmt.Errorf("yy: layout %q at %d: %s", layout, pos, msg)
+}
+
+// compiler compiles layout to sequence of nodes
+type compiler struct {
+	src string
+	i   int
+}
+
+func (c *compiler) error(msg string) error {
+	return layoutError(c.src, c.i, msg)
+}
+
+// spec parses {width justification} of field nd, with run of n chars:
+//
+//	{n} {min,} {min,max}   width, only for single field char
+//	{>c}                   right justified, padded on left with c
+//	{<c}                   left justified, padded on right with c
+//
+// c is optional, default is space, width and justification can be combined: {1,3>_}
+func (c *compiler) spec(nd *node, n int) error {
+	s := c.src[c.i+1:]
+	empty := len(s)
+	if min, rest, ok := parseNum(s, 4); ok {
+		if n > 1 {
+			return c.error("width of run")
+		}
+		max := min
+		if len(rest) > 0 && rest[0] == ',' {
+			rest = rest[1:]
+			max = -1
+			if len(rest) > 0 && rest[0] >= '0' && rest[0] <= '9' {
+				max, rest, _ = parseNum(rest, 4)
+			}
+		}
+		if max == 0 || max > 0 && max < min {
+			return c.error("invalid width")
+		}
+		nd.min, nd.max = min, max
+		s = rest
+	}
+	if len(s) > 0 && (s[0] == '<' || s[0] == '>') {
+		nd.just, nd.pad = s[0], ' '
+		s = s[1:]
+		if len(s) > 1 && s[0] != '}' {
+			r, size := utf8.DecodeRuneInString(s)
+			if _, digit := digitValue(r); digit {
+				return c.error("invalid padding")
+			}
+			nd.pad = r
+			s = s[size:]
+		}
+	}
+	if len(s) == 0 || s[0] != '}' || len(s) == empty {
+		return c.error("invalid {")
+	}
+	c.i = len(c.src) - len(s) + 1
+	return nil
+}
+
+// seq compiles nodes until end of layout or one of stop chars
+func (c *compiler) seq(stop string) ([]node, error) {
+	var seq []node
+	for c.i < len(c.src) {
+		r, size := utf8.DecodeRuneInString(c.src[c.i:])
+		switch {
+		case strings.ContainsRune(stop, r):
+			return seq, nil
+
+		case r == '\\':
+			e, n := utf8.DecodeRuneInString(c.src[c.i+1:])
+			if n == 0 {
+				return nil, c.error("trailing \\")
+			}
+			seq = append(seq, node{kind: nLit, lit: e})
+			c.i += 1 + n
+
+		case r == '[':
+			c.i++
+			g, err := c.seq("]")
+			if err != nil {
+				return nil, err
+			}
+			if c.i == len(c.src) {
+				return nil, c.error("missing ]")
+			}
+			c.i++
+			seq = append(seq, node{kind: nGroup, alts: [][]node{g}, opt: true})
+
+		case r == '(':
+			c.i++
+			var alts [][]node
+			for {
+				g, err := c.seq("|)")
+				if err != nil {
+					return nil, err
+				}
+				if c.i == len(c.src) {
+					return nil, c.error("missing )")
+				}
+				alts = append(alts, g)
+				c.i++
+				if c.src[c.i-1] == ')' {
+					break
+				}
+			}
+			seq = append(seq, node{kind: nGroup, alts: alts})
+
+		case strings.ContainsRune("{}[]()|", r):
+			return nil, c.error("unexpected " + string(r))
+
+		case isLayoutField(r):
+			n := 1
+			for c.i+n < len(c.src) && c.src[c.i+n] == c.src[c.i] {
+				n++
+			}
+			nd := node{kind: nField, c: byte(r), min: n, max: n}
+			c.i += n
+			if c.i < len(c.src) && c.src[c.i] == '{' {
+				if isNameField(nd.c) {
+					return nil, c.error("unexpected {")
+				}
+				if err := c.spec(&nd, n); err != nil {
+					return nil, err
+				}
+			}
+			seq = append(seq, nd)
+
+		default:
+			seq = append(seq, node{kind: nLit, lit: r})
+			c.i += size
+		}
+	}
+	return seq, nil
+}
+
+////////////////////////////////////////////////////////////
+
+// matcher matches data against layout, with backtracking
+type matcher struct {
+	data string
+	loc  *Locale
+	caps []span // matched fields
+
+	// failed are sequences, which do not match at position;
+	// continuation of sequence is given by layout, so failure is not retried
+	failed map[pos]bool
+}
+
+// pos is sequence starting with node n at position j of data
+type pos struct {
+	n *node
+	j int
+}
+
+// accept returns if field c accepts rune r
+func accept(c byte, r rune) bool {
+	switch c {
+	case 'L':
+		return true
+	case 'U':
+		return unicode.IsLetter(r)
+	case 'R':
+		return r == '+' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	_, ok := digitValue(r)
+	return ok
+}
+
+// names returns lengths of month names, weekday names or roman numerals
+// at start of s, longest first
+func (m *matcher) names(c byte, s string) []int {
+	var all [][]string
+	switch c {
+	case 'I':
+		all = [][]string{romanMonths[:]}
+	case 'A':
+		all = [][]string{{"AM", "PM"}}
+	case 'N':
+		all = [][]string{m.loc.Months[:], m.loc.ShortMonths[:]}
+	case 'W':
+		all = [][]string{m.loc.Weekdays[:], m.loc.ShortWeekdays[:]}
+	}
+	var r []int
+	for _, ns := range all {
+		for _, name := range ns {
+			if name == "" {
+				continue
+			}
+			if n, ok := prefixFold([]byte(s), name); ok {
+				r = append(r, n)
+			}
+		}
+	}
+	// longest first
+	for i := 1; i < len(r); i++ {
+		for k := i; k > 0 && r[k] > r[k-1]; k-- {
+			r[k], r[k-1] = r[k-1], r[k]
+		}
+	}
+	return r
+}
+
+// try records field n at data[s:e] and continues with k
+func (m *matcher) try(n *node, s, e int, k func(int) bool) bool {
+	i := len(m.caps)
+	m.caps = append(m.caps, span{n, s, e})
+	if k(e) {
+		return true
+	}
+	m.caps = m.caps[:i]
+	return false
+}
+
+// field matches field n at j
+func (m *matcher) field(n *node, j int, k func(int) bool) bool {
+	if isNameField(n.c) {
+		for _, w := range m.names(n.c, m.data[j:]) {
+			if m.try(n, j, j+w, k) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// ends of possible widths
+	ends := make([]int, 0, 8)
+	e := j
+	padding := n.just == '>' // in padding on left
+	for n.max < 0 || len(ends) < n.max {
+		r, size := utf8.DecodeRuneInString(m.data[e:])
+		if size == 0 {
+			break
+		}
+		switch {
+		case n.just != 0 && r == n.pad && (padding || n.just == '<'):
+			if n.just == '<' {
+				padding = true
+			}
+		case !padding || n.just == '>':
+			if !accept(n.c, r) {
+				goto done
+			}
+			padding = false
+		default:
+			goto done
+		}
+		e += size
+		ends = append(ends, e)
+	}
+done:
+	for w := len(ends); w >= n.min && w > 0; w-- {
+		if m.try(n, j, ends[w-1], k) {
+			return true
+		}
+	}
+	return false
+}
+
+// seq matches sequence of nodes at j, continuing with k
+func (m *matcher) seq(ns []node, j int, k func(int) bool) bool {
+	if len(ns) == 0 {
+		return k(j)
+	}
+	at := pos{&ns[0], j}
+	if m.failed[at] {
+		return false
+	}
+	if m.node(ns, j, k) {
+		return true
+	}
+	if m.failed == nil {
+		m.failed = make(map[pos]bool)
+	}
+	m.failed[at] = true
+	return false
+}
+
+// node matches first node of ns at j, continuing with rest of ns and k
+func (m *matcher) node(ns []node, j int, k func(int) bool) bool {
+	n := &ns[0]
+	next := func(j int) bool {
+		return m.seq(ns[1:], j, k)
+	}
+	switch n.kind {
+	case nLit:
+		r, size := utf8.DecodeRuneInString(m.data[j:])
+		return size > 0 && r == n.lit && next(j+size)
+	case nField:
+		return m.field(n, j, next)
+	case nGroup:
+		for _, a := range n.alts {
+			if m.seq(a, j, next) {
+				return true
+			}
+		}
+		return n.opt && next(j)
+	}
+	return false
+}
+
+// parse parses data to IDate,
+// timezone names, month & weekday names and blank fields are handled by policy of r
+func (l Layout) parse(data string, r *Resolver) (IDate, error) {
+	p, _, err := l.prefix(data, r, func(j int) bool { return j == len(data) })
+	return p, err
+}
+
+// prefix parses start of data to IDate, like parse,
+// end reports if match can end at j, returns length of matched data
+func (l Layout) prefix(data string, r *Resolver, end func(int) bool) (IDate, int, error) {
+	var p IDate
+	loc := r.locale()
+	m := matcher{data: data, loc: loc}
+	n := 0
+	if !m.seq(l.seq, 0, func(j int) bool { n = j; return end(j) }) {
+		return p, 0, errNoMatch
+	}
+
+	var f fieldData
+	for _, c := range m.caps {
+		v := []byte(data[c.s:c.e])
+		switch c.n.c {
+		case 'N', 'W':
+			n, _, _ := loc.name(v, c.n.c == 'N')
+			if c.n.c == 'N' {
+				p.Mo.SetI(n)
+			} else {
+				p.WD.SetI(n)
+			}
+		case 'I':
+			n, _, _ := roman(v)
+			p.Mo.SetI(n)
+		default:
+			if c.n.pad != 0 && c.n.pad != ' ' {
+				// padding is blank
+				v = bytes.ReplaceAll(v, []byte(string(c.n.pad)), []byte{' '})
+			}
+			f[c.n.c] = append(f[c.n.c], v...)
+		}
+	}
+	if l.goTime {
+		if len(f['D']) > 0 || len(f['J']) > 0 {
+			p.WD = Int{}
+		}
+		if z := string(f['L']); isAbbrev(z) {
+			if _, err := r.zones().Lookup(z); err != nil {
+				p.L = Loc{l: time.FixedZone(z, 0)}
+				f['L'] = nil
+			}
+		}
+	}
+	err := setFields(&p, &f, r)
+	return p, n, err
+}
+
+// isAbbrev returns if s looks like zone abbreviation: 3 or more upper case ASCII letters
+func isAbbrev(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return len(s) >= 3
+}
+
+// Parse parses data to IDate, with English names and DefaultZones
+func (l Layout) Parse(data string) (IDate, error) {
+	var r Resolver
+	return l.parse(data, &r)
+}
+
+// Parse parses data according to layout l and converts it to time.Time,
+// rt is reference time
+func (r *Resolver) Parse(l Layout, data string, rt time.Time) (time.Time, error) {
+	return notNull(r.ParseNull(l, data, rt))
+}
+
+// ParseNull is like Parse, but returns null for Sentinels mapped to null
+func (r *Resolver) ParseNull(l Layout, data string, rt time.Time) (NullTime, error) {
+	if t, ok := r.sentinel(data); ok {
+		return t, nil
+	}
+	p, err := l.parse(data, r)
+	if err != nil {
+		return NullTime{}, err
+	}
+	t, err := r.Convert(rt, &p)
+	return NullTime{t, err == nil}, err
+}
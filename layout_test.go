@@ -0,0 +1,205 @@
+package yy
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLayout(t *testing.T) {
+	sofia := mustLoad(t, "Europe/Sofia")
+	tests := []struct {
+		layout, in string
+		out        time.Time
+	}{
+		{"M{1,2}/D{1,2}/YY", "1/5/13", time.Date(2013, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{"M{1,2}/D{1,2}/YY", "10/15/13", time.Date(2013, time.October, 15, 0, 0, 0, 0, time.UTC)},
+		{"D{1,2}.M{1,2}.YYYY h{1,2}:mm", "5.6.2013 9:05", time.Date(2013, time.June, 5, 9, 5, 0, 0, time.UTC)},
+		{"hh:mm:ss.f{1,9}", "10:20:30.5", time.Date(2013, time.June, 10, 10, 20, 30, 500000000, time.UTC)},
+		{"hh:mm:ss.f{1,9}", "10:20:30.123456789", time.Date(2013, time.June, 10, 10, 20, 30, 123456789, time.UTC)},
+		{"hh:mm L{1,}", "10:20 Europe/Sofia", time.Date(2013, time.June, 10, 10, 20, 0, 0, sofia)},
+		{"L{1,} hh:mm", "Europe/Sofia 10:20", time.Date(2013, time.June, 10, 10, 20, 0, 0, sofia)},
+		{"W, D{1,2} N YYYY", "Mon, 3 June 2013", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"D{1,2} N YYYY", "3 Jun 2013", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"D{1,2}.I.YYYY", "3.VI.2013", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"D{1,2}.I.YYYY", "3.IV.2013", time.Date(2013, time.April, 3, 0, 0, 0, 0, time.UTC)},
+		{"YYYY\\MMM", "2013M06", time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"R{1,}", "-3d", time.Date(2013, time.June, 7, 0, 0, 0, 0, time.UTC)},
+		{"hh\\hmm", "10h20", time.Date(2013, time.June, 10, 10, 20, 0, 0, time.UTC)},
+		{"D{1,2}/M{1,2}/YYYY", "３/６/２０１３", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		l, err := Compile(tt.layout)
+		if err != nil {
+			t.Errorf("%s: %v", tt.layout, err)
+			continue
+		}
+		p, err := l.Parse(tt.in)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.layout, tt.in, err)
+			continue
+		}
+		got, err := Convert(ref, &p)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.layout, tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s %s: got %v, want %v", tt.layout, tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestLayoutGroups(t *testing.T) {
+	tests := []struct {
+		layout, in string
+		present    string // present fields
+		out        time.Time
+	}{
+		{"YYMMDD[hhmm[ss]]", "130610", "YMD", time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{"YYMMDD[hhmm[ss]]", "1306101530", "YMDhm", time.Date(2013, time.June, 10, 15, 30, 0, 0, time.UTC)},
+		{"YYMMDD[hhmm[ss]]", "130610153045", "YMDhms", time.Date(2013, time.June, 10, 15, 30, 45, 0, time.UTC)},
+		{"(YYYY|YY)-MM-DD", "2012-06-10", "YMD", time.Date(2012, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{"(YYYY|YY)-MM-DD", "12-06-10", "YMD", time.Date(2012, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{"(MM-DD|J{3})[ hh:mm]", "161", "J", time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{"(MM-DD|J{3})[ hh:mm]", "06-09 10:00", "MDhm", time.Date(2013, time.June, 9, 10, 0, 0, 0, time.UTC)},
+		{"[W, ]D{1,2} N YYYY", "Mon, 3 Jun 2013", "WDMY", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"[W, ]D{1,2} N YYYY", "3 Jun 2013", "DMY", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"hh:mm[:ss[.f{1,9}]]", "10:20:30.25", "hmsf", time.Date(2013, time.June, 10, 10, 20, 30, 250000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		p, err := MustCompile(tt.layout).Parse(tt.in)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.layout, tt.in, err)
+			continue
+		}
+		present := map[byte]bool{
+			'Y': p.Y.Digits() > 0, 'M': p.Mo.Present(), 'D': p.D.Present(), 'J': p.J.Present(), 'W': p.WD.Present(),
+			'h': p.H.Present(), 'm': p.M.Present(), 's': p.S.Present(), 'f': p.F.Present(),
+		}
+		for c, ok := range present {
+			if ok != strings.ContainsRune(tt.present, rune(c)) {
+				t.Errorf("%s %s: %c present %v", tt.layout, tt.in, c, ok)
+			}
+		}
+		got, err := Convert(ref, &p)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.layout, tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s %s: got %v, want %v", tt.layout, tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestLayoutPadding(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	tests := []struct {
+		layout, in string
+		out        time.Time
+	}{
+		{"DD{>}.MM.YYYY", " 3.06.2013", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"D{1,3>*}/MM", "**3/06", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"D{1,3>*}/MM", "3/06", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"MM-DD L{6<_}", "06-03 CET___", time.Date(2013, time.June, 3, 0, 0, 0, 0, cet)},
+		{"hh:mm:ss.fff{<};", "10:20:30.5  ;", time.Date(2013, time.June, 10, 10, 20, 30, 500000000, time.UTC)},
+		// padded year keeps width of layout
+		{"YY{>}MM", " 706", time.Date(2007, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"YYYY{>}MM", "  1306", time.Date(13, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		p, err := MustCompile(tt.layout).Parse(tt.in)
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.layout, tt.in, err)
+			continue
+		}
+		got, err := Convert(ref, &p)
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.layout, tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s %q: got %v, want %v", tt.layout, tt.in, got, tt.out)
+		}
+	}
+
+	// padding on wrong side
+	for _, in := range []string{"3 .06.2013", "3.06.2013"} {
+		if _, err := MustCompile("DD{>}.MM.YYYY").Parse(in); err == nil {
+			t.Errorf("%q: no error", in)
+		}
+	}
+}
+
+func TestLayoutBacktracking(t *testing.T) {
+	// adjacent unbounded fields, failed matches are not retried
+	l := MustCompile("L{1,}L{1,}L{1,}L{1,}L{1,}L{1,}L{1,}L{1,}x")
+	done := make(chan error, 1)
+	go func() {
+		_, err := l.Parse(strings.Repeat("a", 200))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != errNoMatch {
+			t.Errorf("got %v, want %v", err, errNoMatch)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("exponential backtracking")
+	}
+}
+
+func TestLayoutBlank(t *testing.T) {
+	l := MustCompile("YYYY{>}MMDD{>_}")
+	if _, err := l.Parse("    06__"); err == nil {
+		t.Error("blank fields: no error")
+	}
+	r := Resolver{BlankAbsent: true}
+	got, err := r.Parse(l, "    06__", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLayoutNoMatch(t *testing.T) {
+	tests := []struct {
+		layout, in string
+	}{
+		{"M{1,2}/D{1,2}/YY", "123/5/13"},
+		{"M{1,2}/D{1,2}/YY", "1/5/2013"},
+		{"M{1,2}/D{1,2}/YY", "1-5-13"},
+		{"M{1,2}/D{1,2}/YY", "1/5/13 "},
+		{"hh:mm", "1:20"},
+		{"D{1,2} N", "3 Jux"},
+		{"D{1,2}.I", "3.XIII"},
+		{"YYMMDD[hhmm[ss]]", "13061015"},
+		{"YYMMDD[hhmm[ss]]", "1306101530451"},
+		{"(YYYY|YY)-MM-DD", "013-06-10"},
+	}
+	for _, tt := range tests {
+		if _, err := MustCompile(tt.layout).Parse(tt.in); err == nil {
+			t.Errorf("%s %s: no error", tt.layout, tt.in)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, s := range []string{"M{", "M{1,2", "MM{1,2}", "M{2,1}", "M{0}", "N{1,2}", "Y}", "M{a}", "YYYY\\", "YY[MM", "(YY|MM", "YY]", "YY|MM", "(YY]", "[YY)", "D{}", "DD{1,2}", "D{>0}", "D{1,2>ab}", "N{>}"} {
+		if _, err := Compile(s); err == nil {
+			t.Errorf("%s: no error", s)
+		}
+	}
+}
+
+func TestResolverParse(t *testing.T) {
+	r := Resolver{Locale: LookupLocale("de")}
+	got, err := r.Parse(MustCompile("D{1,2}. N YYYY"), "3. März 2013", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2013, time.March, 3, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
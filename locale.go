@@ -0,0 +1,258 @@
+package yy
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Locale is table of month & weekday names and words for parsing natural language expressions.
+type Locale struct {
+	// Months and ShortMonths are full and abbreviated month names, January first
+	Months, ShortMonths [12]string
+
+	// Weekdays and ShortWeekdays are full and abbreviated weekday names, Sunday first
+	Weekdays, ShortWeekdays [7]string
+
+	// Words maps lower case words and phrases (words separated by single space)
+	// to their meaning in natural language expressions.
+	Words map[string]Word
+}
+
+// WordKind is kind of word in natural language expression
+type WordKind int
+
+// Kinds of words
+const (
+	WordFiller  WordKind = iota // ignored ("of", "the")
+	WordDays                    // fixed offset of N days ("today", "yesterday")
+	WordNumber                  // number N ("a", "three")
+	WordUnit                    // unit N ("days", "month")
+	WordWeekday                 // weekday N ("friday", "fri")
+	WordNext                    // "next"
+	WordLast                    // "last", "previous"
+	WordThis                    // "this", "current"
+	WordAgo                     // suffix, offset is in past ("ago")
+	WordIn                      // prefix or suffix, offset is in future ("in", "from now")
+	WordStart                   // start of period ("start", "beginning")
+	WordEnd                     // end of period ("end")
+	WordFirst                   // first business day ("first")
+)
+
+// Word is meaning of word in natural language expression
+type Word struct {
+	Kind WordKind
+	N    int
+}
+
+// English is built in English locale
+var English = &Locale{
+	Months: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+		"Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	ShortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	Words: map[string]Word{
+		"today":                {WordDays, 0},
+		"yesterday":            {WordDays, -1},
+		"tomorrow":             {WordDays, 1},
+		"day before yesterday": {WordDays, -2},
+		"day after tomorrow":   {WordDays, 2},
+
+		"next":      {WordNext, 0},
+		"last":      {WordLast, 0},
+		"previous":  {WordLast, 0},
+		"prev":      {WordLast, 0},
+		"this":      {WordThis, 0},
+		"current":   {WordThis, 0},
+		"ago":       {WordAgo, 0},
+		"in":        {WordIn, 0},
+		"from now":  {WordIn, 0},
+		"later":     {WordIn, 0},
+		"start":     {WordStart, 0},
+		"begin":     {WordStart, 0},
+		"beginning": {WordStart, 0},
+		"end":       {WordEnd, 0},
+		"first":     {WordFirst, 0},
+		"of":        {WordFiller, 0},
+		"the":       {WordFiller, 0},
+
+		"a":      {WordNumber, 1},
+		"an":     {WordNumber, 1},
+		"one":    {WordNumber, 1},
+		"two":    {WordNumber, 2},
+		"three":  {WordNumber, 3},
+		"four":   {WordNumber, 4},
+		"five":   {WordNumber, 5},
+		"six":    {WordNumber, 6},
+		"seven":  {WordNumber, 7},
+		"eight":  {WordNumber, 8},
+		"nine":   {WordNumber, 9},
+		"ten":    {WordNumber, 10},
+		"eleven": {WordNumber, 11},
+		"twelve": {WordNumber, 12},
+
+		"hour":          {WordUnit, int(UnitHour)},
+		"hours":         {WordUnit, int(UnitHour)},
+		"day":           {WordUnit, int(UnitDay)},
+		"days":          {WordUnit, int(UnitDay)},
+		"week":          {WordUnit, int(UnitWeek)},
+		"weeks":         {WordUnit, int(UnitWeek)},
+		"month":         {WordUnit, int(UnitMonth)},
+		"months":        {WordUnit, int(UnitMonth)},
+		"quarter":       {WordUnit, int(UnitQuarter)},
+		"quarters":      {WordUnit, int(UnitQuarter)},
+		"year":          {WordUnit, int(UnitYear)},
+		"years":         {WordUnit, int(UnitYear)},
+		"business day":  {WordUnit, int(UnitBusinessDay)},
+		"business days": {WordUnit, int(UnitBusinessDay)},
+		"working day":   {WordUnit, int(UnitBusinessDay)},
+		"working days":  {WordUnit, int(UnitBusinessDay)},
+
+		"monday":    {WordWeekday, int(time.Monday)},
+		"mon":       {WordWeekday, int(time.Monday)},
+		"tuesday":   {WordWeekday, int(time.Tuesday)},
+		"tue":       {WordWeekday, int(time.Tuesday)},
+		"tues":      {WordWeekday, int(time.Tuesday)},
+		"wednesday": {WordWeekday, int(time.Wednesday)},
+		"wed":       {WordWeekday, int(time.Wednesday)},
+		"thursday":  {WordWeekday, int(time.Thursday)},
+		"thu":       {WordWeekday, int(time.Thursday)},
+		"thur":      {WordWeekday, int(time.Thursday)},
+		"thurs":     {WordWeekday, int(time.Thursday)},
+		"friday":    {WordWeekday, int(time.Friday)},
+		"fri":       {WordWeekday, int(time.Friday)},
+		"saturday":  {WordWeekday, int(time.Saturday)},
+		"sat":       {WordWeekday, int(time.Saturday)},
+		"sunday":    {WordWeekday, int(time.Sunday)},
+		"sun":       {WordWeekday, int(time.Sunday)},
+	},
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]*Locale{
+		"en": English,
+		"bg": {
+			Months: [12]string{"януари", "февруари", "март", "април", "май", "юни",
+				"юли", "август", "септември", "октомври", "ноември", "декември"},
+			ShortMonths: [12]string{"яну", "фев", "мар", "апр", "май", "юни",
+				"юли", "авг", "сеп", "окт", "ное", "дек"},
+			Weekdays:      [7]string{"неделя", "понеделник", "вторник", "сряда", "четвъртък", "петък", "събота"},
+			ShortWeekdays: [7]string{"нд", "пн", "вт", "ср", "чт", "пт", "сб"},
+		},
+		"de": {
+			Months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+				"Juli", "August", "September", "Oktober", "November", "Dezember"},
+			ShortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun",
+				"Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+			Weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			ShortWeekdays: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		},
+		"fr": {
+			Months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin",
+				"juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			ShortMonths: [12]string{"janv", "févr", "mars", "avr", "mai", "juin",
+				"juil", "août", "sept", "oct", "nov", "déc"},
+			Weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			ShortWeekdays: [7]string{"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+		},
+		"es": {
+			Months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+				"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun",
+				"jul", "ago", "sep", "oct", "nov", "dic"},
+			Weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			ShortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		},
+		"it": {
+			Months: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+				"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+			ShortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu",
+				"lug", "ago", "set", "ott", "nov", "dic"},
+			Weekdays:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+			ShortWeekdays: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		},
+	}
+)
+
+// RegisterLocale registers locale l under language tag (e.g. "en", "bg", "de"),
+// replacing previously registered locale
+func RegisterLocale(tag string, l *Locale) {
+	localesMu.Lock()
+	locales[strings.ToLower(tag)] = l
+	localesMu.Unlock()
+}
+
+// LookupLocale returns locale registered for language tag, or nil.
+// For tags with region ("de-AT") locale of language is returned, if there is no locale for region.
+func LookupLocale(tag string) *Locale {
+	tag = strings.ToLower(tag)
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	if l, ok := locales[tag]; ok {
+		return l
+	}
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		return locales[tag[:i]]
+	}
+	return nil
+}
+
+// prefixFold returns length in bytes of prefix of s, equal to p under Unicode case folding
+func prefixFold(s []byte, p string) (int, bool) {
+	n := 0
+	for _, pr := range p {
+		if n >= len(s) {
+			return 0, false
+		}
+		sr, size := utf8.DecodeRune(s[n:])
+		if sr != pr && !equalFold(sr, pr) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// equalFold reports if runes are equal under Unicode case folding
+func equalFold(a, b rune) bool {
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// name matches longest month (or weekday) name at start of s,
+// returns month 1..12 (or weekday 0..6) and length of name in bytes
+func (l *Locale) name(s []byte, month bool) (int, int, bool) {
+	var names [][]string
+	if month {
+		names = [][]string{l.Months[:], l.ShortMonths[:]}
+	} else {
+		names = [][]string{l.Weekdays[:], l.ShortWeekdays[:]}
+	}
+	v, max := 0, 0
+	for _, ns := range names {
+		for i, name := range ns {
+			if name == "" {
+				continue
+			}
+			if n, ok := prefixFold(s, name); ok && n > max {
+				v, max = i, n
+			}
+		}
+	}
+	if max == 0 {
+		return 0, 0, false
+	}
+	if month {
+		v++
+	}
+	return v, max, true
+}
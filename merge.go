@@ -0,0 +1,179 @@
+package yy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MergeRule chooses component of merged dates, when both have it with different values
+type MergeRule int
+
+// Merge rules
+const (
+	MergeKeep    MergeRule = iota // keep component of receiver
+	MergeReplace                  // replace with component of other date
+)
+
+// ConflictError is returned by Merge, when both dates have component with different values
+type ConflictError struct {
+	Fields []string // names of components in IDate ("Y", "Mo", "D", "L")
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflicting date components %s", strings.Join(e.Fields, ", "))
+}
+
+// sameLoc returns if a and b are same timezone
+func sameLoc(a, b *Loc) bool {
+	if a.l == b.l {
+		return true
+	}
+	ao, aok := a.Offset()
+	bo, bok := b.Offset()
+	if aok || bok {
+		return aok && bok && ao == bo
+	}
+	return a.l.String() == b.l.String()
+}
+
+// sameYear returns if a and b are same year, year with less digits is same as
+// last digits of other ("13" and "2013")
+func sameYear(a, b *Year) bool {
+	if a.digits > b.digits {
+		a, b = b, a
+	}
+	m := 1
+	for i := int8(0); i < a.digits; i++ {
+		m *= 10
+	}
+	return b.y%m == a.y
+}
+
+// dayConflict returns if day of year of a is not month and day of b,
+// in year y when it is complete, otherwise in both common and leap year
+func dayConflict(a, b *IDate, y *Year) bool {
+	if !a.J.present || !b.Mo.present && !b.D.present {
+		return false
+	}
+	years := []int{2001, 2004}
+	if y.digits >= 4 {
+		years = []int{y.y}
+	}
+	for _, yy := range years {
+		t := time.Date(yy, time.January, a.J.val, 0, 0, 0, 0, time.UTC)
+		if (!b.Mo.present || int(t.Month()) == b.Mo.val) && (!b.D.present || t.Day() == b.D.val) {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge merges components of o into p: components missing in p are taken from o
+// (date from one field, time from other, timezone from third).
+// Components present in both with different values are conflicts,
+// they are resolved by rule, and reported with *ConflictError.
+// Years are not conflicting, if shorter one is last digits of other ("13" and "2013"),
+// and longer is kept.
+// Day of year of one date is conflict "J" with month and day of other, when it is
+// other calendar date, then by rule either day of year or month and day are kept.
+func (p *IDate) Merge(o *IDate, rule MergeRule) error {
+	var conflicts []string
+
+	oc := *o
+	o = &oc // conflicting components of o are dropped
+	y := &p.Y
+	if o.Y.digits > y.digits {
+		y = &o.Y
+	}
+	switch {
+	case dayConflict(p, o, y):
+		conflicts = append(conflicts, "J")
+		if rule == MergeReplace {
+			p.J = Int{}
+		} else {
+			o.Mo, o.D = Int{}, Int{}
+		}
+	case dayConflict(o, p, y):
+		conflicts = append(conflicts, "J")
+		if rule == MergeReplace {
+			p.Mo, p.D = Int{}, Int{}
+		} else {
+			o.J = Int{}
+		}
+	}
+
+	ints := []struct {
+		name string
+		a, b *Int
+	}{
+		{"J", &p.J, &o.J}, {"Mo", &p.Mo, &o.Mo}, {"D", &p.D, &o.D},
+		{"H", &p.H, &o.H}, {"M", &p.M, &o.M}, {"S", &p.S, &o.S},
+		{"WD", &p.WD, &o.WD}, {"F", &p.F.Int, &o.F.Int},
+	}
+	for _, c := range ints {
+		switch {
+		case !c.b.present:
+		case !c.a.present:
+			*c.a = *c.b
+		case c.a.val != c.b.val:
+			conflicts = append(conflicts, c.name)
+			if rule == MergeReplace {
+				*c.a = *c.b
+			}
+		}
+	}
+
+	switch {
+	case o.Y.digits == 0:
+	case p.Y.digits == 0:
+		p.Y = o.Y
+	case sameYear(&p.Y, &o.Y):
+		if o.Y.digits > p.Y.digits {
+			p.Y = o.Y
+		}
+	default:
+		conflicts = append(conflicts, "Y")
+		if rule == MergeReplace {
+			p.Y = o.Y
+		}
+	}
+
+	switch {
+	case !o.WN.present:
+	case !p.WN.present:
+		p.WN = o.WN
+	case p.WN != o.WN:
+		conflicts = append(conflicts, "WN")
+		if rule == MergeReplace {
+			p.WN = o.WN
+		}
+	}
+
+	switch {
+	case !o.L.Present():
+	case !p.L.Present():
+		p.L = o.L
+	case !sameLoc(&p.L, &o.L):
+		conflicts = append(conflicts, "L")
+		if rule == MergeReplace {
+			p.L = o.L
+		}
+	}
+
+	switch {
+	case !o.R.present:
+	case !p.R.present:
+		p.R = o.R
+	case p.R != o.R:
+		conflicts = append(conflicts, "R")
+		if rule == MergeReplace {
+			p.R = o.R
+		}
+	}
+
+	if conflicts != nil {
+		return &ConflictError{Fields: conflicts}
+	}
+	return nil
+}
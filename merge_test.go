@@ -0,0 +1,105 @@
+package yy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	var r Resolver
+	parse := func(data, layout string) IDate {
+		p, err := MustCompile(layout).parse(data, &r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// date, time and timezone from separate fields
+	p := parse("2013-06-03", "YYYY-MM-DD")
+	tm := parse("10:20:30", "hh:mm:ss")
+	z := parse("+02:00", "L{6}")
+	if err := p.Merge(&tm, MergeKeep); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Merge(&z, MergeKeep); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Convert(ref, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2013, time.June, 3, 8, 20, 30, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// redundant fields, short year is compatible with full year
+	p = parse("130603", "YYMMDD")
+	q := parse("2013-06-03", "YYYY-MM-DD")
+	if err := p.Merge(&q, MergeKeep); err != nil || p.Y.Digits() != 4 {
+		t.Errorf("redundant: %v, year of %d digits", err, p.Y.Digits())
+	}
+
+	tests := []struct {
+		rule   MergeRule
+		day    int
+		fields []string
+	}{
+		{MergeKeep, 3, []string{"D", "Y", "L"}},
+		{MergeReplace, 4, []string{"D", "Y", "L"}},
+	}
+	for _, tt := range tests {
+		p = parse("2013-06-03 UTC", "YYYY-MM-DD L{1,}")
+		q = parse("2012-06-04 10:00 +02:00", "YYYY-MM-DD hh:mm L{6}")
+		err := p.Merge(&q, tt.rule)
+		var ce *ConflictError
+		if !errors.As(err, &ce) || !reflect.DeepEqual(ce.Fields, tt.fields) {
+			t.Errorf("rule %d: conflicts %v", tt.rule, err)
+		}
+		if p.D.Get() != tt.day || !p.H.Present() {
+			t.Errorf("rule %d: day %d, hour %v", tt.rule, p.D.Get(), p.H.Present())
+		}
+	}
+
+	// day of year with month and day
+	p = parse("2013-154", "YYYY-JJJ")
+	q = parse("06-03", "MM-DD")
+	if err := p.Merge(&q, MergeKeep); err != nil {
+		t.Errorf("same day: %v", err)
+	}
+	for _, tt := range []struct {
+		rule MergeRule
+		want time.Time
+	}{
+		{MergeKeep, time.Date(2013, time.April, 10, 0, 0, 0, 0, time.UTC)},
+		{MergeReplace, time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+	} {
+		p = parse("2013-100", "YYYY-JJJ")
+		q = parse("06-03", "MM-DD")
+		err := p.Merge(&q, tt.rule)
+		var ce *ConflictError
+		if !errors.As(err, &ce) || !reflect.DeepEqual(ce.Fields, []string{"J"}) {
+			t.Errorf("rule %d: conflicts %v", tt.rule, err)
+		}
+		got, err := Convert(ref, &p)
+		if err != nil {
+			t.Errorf("rule %d: %v", tt.rule, err)
+		} else if !got.Equal(tt.want) {
+			t.Errorf("rule %d: got %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+
+	// day 60 is February 29 only in leap year
+	p = parse("060", "JJJ")
+	q = parse("02-29", "MM-DD")
+	if err := p.Merge(&q, MergeKeep); err != nil {
+		t.Errorf("without year: %v", err)
+	}
+	p = parse("2013-060", "YYYY-JJJ")
+	q = parse("02-29", "MM-DD")
+	if err := p.Merge(&q, MergeKeep); err == nil {
+		t.Error("2013-060: no conflict")
+	}
+}
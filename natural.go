@@ -0,0 +1,189 @@
+package yy
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNatural = errors.New("unrecognized natural language expression")
+
+// longest phrase in words
+const maxPhrase = 4
+
+// words splits s to words and phrases known in locale
+func (l *Locale) words(s string) ([]Word, error) {
+	f := strings.Fields(strings.ToLower(s))
+	for i := range f {
+		f[i] = strings.Trim(f[i], ".,;")
+	}
+
+	var r []Word
+	for i := 0; i < len(f); {
+		if n, err := strconv.Atoi(f[i]); err == nil && n >= 0 {
+			r = append(r, Word{WordNumber, n})
+			i++
+			continue
+		}
+		j := i + maxPhrase
+		if j > len(f) {
+			j = len(f)
+		}
+		for ; j > i; j-- {
+			if w, ok := l.Words[strings.Join(f[i:j], " ")]; ok {
+				if w.Kind != WordFiller {
+					r = append(r, w)
+				}
+				break
+			}
+		}
+		if j == i {
+			return nil, errNatural
+		}
+		i = j
+	}
+	return r, nil
+}
+
+// dir returns direction of next/last/this
+func dir(w Word) (int, bool) {
+	switch w.Kind {
+	case WordNext:
+		return 1, true
+	case WordLast:
+		return -1, true
+	case WordThis:
+		return 0, true
+	}
+	return 0, false
+}
+
+// period parses [next|last|this] unit, returns n units and unit
+func period(w []Word) (Period, Unit, bool) {
+	n := 0
+	if len(w) == 2 {
+		var ok bool
+		if n, ok = dir(w[0]); !ok {
+			return Period{}, 0, false
+		}
+		w = w[1:]
+	}
+	if len(w) != 1 || w[0].Kind != WordUnit {
+		return Period{}, 0, false
+	}
+	u := Unit(w[0].N)
+	p, ok := u.period(n)
+	return p, u, ok
+}
+
+// natural sets r from natural language expression
+func (r *Rel) natural(w []Word) bool {
+	switch {
+	case len(w) == 1 && w[0].Kind == WordDays:
+		// today, yesterday
+		r.SetI(w[0].N)
+		return true
+
+	case len(w) == 1 && w[0].Kind == WordWeekday:
+		// friday, is friday in current week
+		r.SetP(Period{})
+		r.wdSet, r.wd = true, time.Weekday(w[0].N)
+		return true
+
+	case len(w) == 2 && w[1].Kind == WordWeekday:
+		// next friday
+		n, ok := dir(w[0])
+		if !ok {
+			return false
+		}
+		r.SetP(Period{})
+		r.wdSet, r.wd, r.wdN = true, time.Weekday(w[1].N), n
+		return true
+
+	case len(w) == 3 && w[0].Kind == WordNumber && w[1].Kind == WordUnit && (w[2].Kind == WordAgo || w[2].Kind == WordIn),
+		len(w) == 3 && w[0].Kind == WordIn && w[1].Kind == WordNumber && w[2].Kind == WordUnit:
+		// 3 days ago, in 2 weeks, 2 weeks from now
+		n, u, sign := w[0].N, Unit(w[1].N), -1
+		if w[0].Kind == WordIn {
+			n, u, sign = w[1].N, Unit(w[2].N), 1
+		} else if w[2].Kind == WordIn {
+			sign = 1
+		}
+		p, ok := u.period(sign * n)
+		if ok {
+			r.SetP(p)
+		}
+		return ok
+
+	case len(w) >= 2 && (w[0].Kind == WordStart || w[0].Kind == WordEnd):
+		// end of month, start of next year
+		p, u, ok := period(w[1:])
+		if !ok || u == UnitHour || u == UnitDay || u == UnitBusinessDay {
+			return false
+		}
+		r.SetP(p)
+		r.edge, r.end = u, w[0].Kind == WordEnd
+		return true
+
+	case len(w) >= 2 && w[1].Kind == WordUnit && Unit(w[1].N) == UnitBusinessDay:
+		if len(w) == 2 && (w[0].Kind == WordNext || w[0].Kind == WordLast) {
+			// next business day, last business day
+			n, _ := dir(w[0])
+			r.SetP(Period{BusinessDays: n})
+			return true
+		}
+		// first business day of month, last business day of next quarter
+		if w[0].Kind != WordFirst && w[0].Kind != WordLast {
+			return false
+		}
+		p, u, ok := period(w[2:])
+		if !ok || u == UnitHour || u == UnitDay || u == UnitBusinessDay {
+			return false
+		}
+		r.SetP(p)
+		r.edge, r.end, r.snap = u, w[0].Kind == WordLast, 1
+		if r.end {
+			r.snap = -1
+		}
+		return true
+
+	case len(w) == 2 && w[1].Kind == WordUnit:
+		// next month, last year
+		p, _, ok := period(w)
+		if ok {
+			r.SetP(p)
+		}
+		return ok
+	}
+	return false
+}
+
+// ParseNatural parses natural language expression with words from locale l
+// (English if nil) to IDate with relative offset, resolve it with Convert.
+//
+// Accepted expressions are (in English):
+//
+//	today, yesterday, tomorrow, day before yesterday, day after tomorrow
+//	N units ago, in N units, N units from now      units are hours, days, weeks, months, quarters, years, business days
+//	next|last|this week|month|quarter|year         same day in next/last period
+//	friday, next|last|this friday                  this is weekday in same week (Monday to Sunday)
+//	start|end of [next|last|this] week|month|quarter|year
+//	next|last business day
+//	first|last business day of [next|last|this] week|month|quarter|year
+//
+// N is number in digits or words (a, one, two ... twelve).
+func ParseNatural(s string, l *Locale) (IDate, error) {
+	var p IDate
+	if l == nil {
+		l = English
+	}
+	w, err := l.words(s)
+	if err != nil {
+		return p, err
+	}
+	if !p.R.natural(w) {
+		return p, errNatural
+	}
+	return p, nil
+}
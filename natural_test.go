@@ -0,0 +1,99 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseNatural(t *testing.T) {
+	// reference is Monday 2013-06-10 23:01:02
+	d := func(y int, m time.Month, dd int) time.Time { return time.Date(y, m, dd, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		in  string
+		out time.Time
+	}{
+		{"today", d(2013, 6, 10)},
+		{"Yesterday", d(2013, 6, 9)},
+		{"tomorrow", d(2013, 6, 11)},
+		{"the day before yesterday", d(2013, 6, 8)},
+		{"next Friday", d(2013, 6, 14)},
+		{"last Friday", d(2013, 6, 7)},
+		{"this friday", d(2013, 6, 14)},
+		{"friday", d(2013, 6, 14)},
+		{"next monday", d(2013, 6, 17)},
+		{"last mon", d(2013, 6, 3)},
+		{"this Sunday", d(2013, 6, 16)},
+		{"3 days ago", d(2013, 6, 7)},
+		{"three days ago", d(2013, 6, 7)},
+		{"in 2 weeks", d(2013, 6, 24)},
+		{"2 weeks from now", d(2013, 6, 24)},
+		{"a month ago", d(2013, 5, 10)},
+		{"in 5 business days", d(2013, 6, 17)},
+		{"2 hours ago", time.Date(2013, 6, 10, 21, 1, 2, 3, time.UTC)},
+		{"next month", d(2013, 7, 10)},
+		{"last year", d(2012, 6, 10)},
+		{"end of month", d(2013, 6, 30)},
+		{"end of the month", d(2013, 6, 30)},
+		{"start of next month", d(2013, 7, 1)},
+		{"end of last year", d(2012, 12, 31)},
+		{"end of quarter", d(2013, 6, 30)},
+		{"beginning of next quarter", d(2013, 7, 1)},
+		{"beginning of this week", d(2013, 6, 10)},
+		{"end of week", d(2013, 6, 16)},
+		{"last business day", d(2013, 6, 7)},
+		{"next business day", d(2013, 6, 11)},
+		{"last business day of month", d(2013, 6, 28)},
+		{"first business day of next month", d(2013, 7, 1)},
+		{"first working day of last month", d(2013, 5, 1)},
+		{"last business day of the year", d(2013, 12, 31)},
+	}
+	for _, tt := range tests {
+		p, err := ParseNatural(tt.in, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		got, err := Convert(ref, &p)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+	}
+
+	for _, in := range []string{"", "blah", "next", "3 days", "end of day", "first business day", "next 3 days", "ago 3 days"} {
+		if p, err := ParseNatural(in, nil); err == nil {
+			t.Errorf("%q: expected error, got %+v", in, p.R)
+		}
+	}
+}
+
+func TestParseNaturalLocale(t *testing.T) {
+	de := &Locale{Words: map[string]Word{
+		"heute":    {WordDays, 0},
+		"gestern":  {WordDays, -1},
+		"nächsten": {WordNext, 0},
+		"freitag":  {WordWeekday, int(time.Friday)},
+	}}
+	RegisterLocale("x-de", de)
+	if LookupLocale("X-DE") != de || LookupLocale("EN") != English || LookupLocale("xx") != nil {
+		t.Fatal("locale registry")
+	}
+
+	for in, out := range map[string]time.Time{
+		"gestern":          time.Date(2013, 6, 9, 0, 0, 0, 0, time.UTC),
+		"nächsten Freitag": time.Date(2013, 6, 14, 0, 0, 0, 0, time.UTC),
+	} {
+		p, err := ParseNatural(in, de)
+		if err != nil {
+			t.Errorf("%s: %v", in, err)
+			continue
+		}
+		got, err := Convert(ref, &p)
+		if err != nil || !got.Equal(out) {
+			t.Errorf("%s: got %v %v, want %v", in, got, err, out)
+		}
+	}
+}
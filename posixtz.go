@@ -0,0 +1,293 @@
+package yy
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+var errInvalidZone = errors.New("invalid timezone")
+
+// parseNum parses decimal number of at most max digits from start of s,
+// returns number, rest of s and if there was any digit
+func parseNum(s string, max int) (int, string, bool) {
+	n, i := 0, 0
+	for ; i < len(s) && i < max && s[i] >= '0' && s[i] <= '9'; i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, s[i:], i > 0
+}
+
+// parseOffset parses UTC offset east of UTC in seconds:
+//
+//	[+-]h  [+-]hh  [+-]hmm  [+-]hhmm  [+-]hhmmss  [+-]h:mm  [+-]hh:mm  [+-]hh:mm:ss
+func parseOffset(s string) (int, bool) {
+	if len(s) < 2 || (s[0] != '+' && s[0] != '-') {
+		return 0, false
+	}
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+	s = s[1:]
+
+	var h, m, sec int
+	if strings.IndexByte(s, ':') >= 0 {
+		var ok bool
+		var rest string
+		if h, rest, ok = parseNum(s, 2); !ok || len(rest) != 3 && len(rest) != 6 || rest[0] != ':' {
+			return 0, false
+		}
+		if m, rest, ok = parseNum(rest[1:], 2); !ok || len(rest) != 0 && len(rest) != 3 {
+			return 0, false
+		}
+		if len(rest) > 0 {
+			if rest[0] != ':' {
+				return 0, false
+			}
+			if sec, rest, ok = parseNum(rest[1:], 2); !ok || rest != "" {
+				return 0, false
+			}
+		}
+	} else {
+		n, rest, ok := parseNum(s, 6)
+		if !ok || rest != "" {
+			return 0, false
+		}
+		switch len(s) {
+		case 1, 2:
+			h = n
+		case 3, 4:
+			h, m = n/100, n%100
+		case 6:
+			h, m, sec = n/10000, n/100%100, n%100
+		default:
+			return 0, false
+		}
+	}
+	if h > 24 || h == 24 && m+sec > 0 || m > 59 || sec > 59 {
+		return 0, false
+	}
+	return sign * (h*3600 + m*60 + sec), true
+}
+
+// utcOffset parses offsets written as "UTC+2", "GMT-03:30", "UT+05:30".
+// Sign is as in ISO 8601 (east of UTC is positive), not as in POSIX TZ.
+func utcOffset(s string) (int, bool) {
+	for _, p := range []string{"UTC", "GMT", "UT"} {
+		if len(s) > len(p) && strings.EqualFold(s[:len(p)], p) && (s[len(p)] == '+' || s[len(p)] == '-') {
+			return parseOffset(s[len(p):])
+		}
+	}
+	return 0, false
+}
+
+// offsetZone parses UTC offset "+02:00", "-0530" or "UTC+2", as in parseOffset & utcOffset
+func offsetZone(s string) (int, bool) {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		return parseOffset(s)
+	}
+	return utcOffset(s)
+}
+
+////////////////////////////////////////////////////////////
+
+// posixTZ is parsed POSIX TZ string, e.g. "EST5EDT,M3.2.0,M11.1.0"
+type posixTZ struct {
+	std, dst       string
+	stdOff, dstOff int // seconds east of UTC
+}
+
+// name parses zone name: [A-Za-z]{3,} or <[A-Za-z0-9+-]{3,}>
+func (p *posixTZ) name(s string) (string, string, bool) {
+	if len(s) > 0 && s[0] == '<' {
+		i := strings.IndexByte(s, '>')
+		if i < 4 {
+			return "", s, false
+		}
+		for _, c := range s[1:i] {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '+' || c == '-') {
+				return "", s, false
+			}
+		}
+		return s[1:i], s[i+1:], true
+	}
+	i := 0
+	for i < len(s) && (s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z') {
+		i++
+	}
+	if i < 3 {
+		return "", s, false
+	}
+	return s[:i], s[i:], true
+}
+
+// hms parses [+-]hh[:mm[:ss]] up to maxH hours, returns seconds
+func (p *posixTZ) hms(s string, maxH int) (int, string, bool) {
+	sign := 1
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+	h, s, ok := parseNum(s, 3)
+	if !ok || h > maxH {
+		return 0, s, false
+	}
+	var m, sec int
+	if len(s) > 0 && s[0] == ':' {
+		if m, s, ok = parseNum(s[1:], 2); !ok || m > 59 {
+			return 0, s, false
+		}
+		if len(s) > 0 && s[0] == ':' {
+			if sec, s, ok = parseNum(s[1:], 2); !ok || sec > 59 {
+				return 0, s, false
+			}
+		}
+	}
+	return sign * (h*3600 + m*60 + sec), s, true
+}
+
+// rule parses Jn, n or Mm.w.d, with optional /time
+func (p *posixTZ) rule(s string) (string, bool) {
+	var ok bool
+	var n, w, d int
+	switch {
+	case len(s) > 0 && s[0] == 'J':
+		if n, s, ok = parseNum(s[1:], 3); !ok || n < 1 || n > 365 {
+			return s, false
+		}
+	case len(s) > 0 && s[0] == 'M':
+		if n, s, ok = parseNum(s[1:], 2); !ok || n < 1 || n > 12 || len(s) == 0 || s[0] != '.' {
+			return s, false
+		}
+		if w, s, ok = parseNum(s[1:], 1); !ok || w < 1 || w > 5 || len(s) == 0 || s[0] != '.' {
+			return s, false
+		}
+		if d, s, ok = parseNum(s[1:], 1); !ok || d > 6 {
+			return s, false
+		}
+	default:
+		if n, s, ok = parseNum(s, 3); !ok || n > 365 {
+			return s, false
+		}
+	}
+	if len(s) > 0 && s[0] == '/' {
+		if _, s, ok = p.hms(s[1:], 167); !ok {
+			return s, false
+		}
+	}
+	return s, true
+}
+
+// parse parses POSIX TZ string:
+//
+//	std offset [dst [offset] [,start[/time],end[/time]]]
+func (p *posixTZ) parse(s string) bool {
+	var ok bool
+	var off int
+	if p.std, s, ok = p.name(s); !ok {
+		return false
+	}
+	if off, s, ok = p.hms(s, 24); !ok {
+		return false
+	}
+	p.stdOff = -off
+	if s == "" {
+		return true
+	}
+	if p.dst, s, ok = p.name(s); !ok {
+		return false
+	}
+	p.dstOff = p.stdOff + 3600
+	if len(s) > 0 && s[0] != ',' {
+		if off, s, ok = p.hms(s, 24); !ok {
+			return false
+		}
+		p.dstOff = -off
+	}
+	if s == "" {
+		// default rules as in tzcode
+		return true
+	}
+	if s[0] != ',' {
+		return false
+	}
+	if s, ok = p.rule(s[1:]); !ok || len(s) == 0 || s[0] != ',' {
+		return false
+	}
+	s, ok = p.rule(s[1:])
+	return ok && s == ""
+}
+
+// tzif builds TZif (version 2) data, with single transition at year 1
+// to standard time and POSIX TZ string footer describing all later time.
+func (p *posixTZ) tzif(tz string) []byte {
+	type ttinfo struct {
+		off  int
+		dst  byte
+		name string
+	}
+	types := []ttinfo{{p.stdOff, 0, p.std}}
+	if p.dst != "" {
+		types = append(types, ttinfo{p.dstOff, 1, p.dst})
+	}
+	var chars []byte
+	for i := range types {
+		chars = append(chars, types[i].name...)
+		chars = append(chars, 0)
+	}
+
+	var b []byte
+	u32 := func(v int) { b = binary.BigEndian.AppendUint32(b, uint32(int32(v))) }
+	header := func(timecnt int) {
+		b = append(b, "TZif2"...)
+		b = append(b, make([]byte, 15)...)
+		for _, n := range []int{0, 0, 0, timecnt, len(types), len(chars)} {
+			u32(n)
+		}
+	}
+	block := func() {
+		idx := 0
+		for i := range types {
+			u32(types[i].off)
+			b = append(b, types[i].dst, byte(idx))
+			idx += len(types[i].name) + 1
+		}
+		b = append(b, chars...)
+	}
+
+	// version 1 data, without transitions
+	header(0)
+	block()
+	// version 2 data
+	header(1)
+	b = binary.BigEndian.AppendUint64(b, uint64(time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC).Unix()))
+	b = append(b, 0)
+	block()
+	b = append(b, '\n')
+	b = append(b, tz...)
+	b = append(b, '\n')
+	return b
+}
+
+// isPosixTZ reports if s looks like POSIX TZ string, not zone name.
+// Only strings with daylight saving time ("EST5EDT", "CET-1CEST,M3.5.0,M10.5.0/3")
+// or with quoted name ("<+0330>-3:30") are POSIX TZ strings, in "JST-9" or "UTC-2"
+// sign of offset is ambiguous.
+func isPosixTZ(s string) bool {
+	var p posixTZ
+	return strings.ContainsAny(s, "0123456789") && p.parse(s) && (p.dst != "" || s[0] == '<')
+}
+
+// posixLocation returns location described by POSIX TZ string s,
+// including daylight saving time transitions
+func posixLocation(s string) (*time.Location, error) {
+	var p posixTZ
+	if !p.parse(s) {
+		return nil, errInvalidZone
+	}
+	return time.LoadLocationFromTZData(s, p.tzif(s))
+}
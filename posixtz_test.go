@@ -0,0 +1,101 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocSet(t *testing.T) {
+	at := []time.Time{
+		time.Date(2013, time.January, 10, 12, 0, 0, 0, time.UTC),
+		time.Date(2013, time.June, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		in   string
+		off  [2]int // offsets in January & June
+		name [2]string
+	}{
+		{"Z", [2]int{0, 0}, [2]string{"UTC", "UTC"}},
+		{"z", [2]int{0, 0}, [2]string{"UTC", "UTC"}},
+		{"+05", [2]int{5 * 3600, 5 * 3600}, [2]string{}},
+		{"-5", [2]int{-5 * 3600, -5 * 3600}, [2]string{}},
+		{"+0530", [2]int{19800, 19800}, [2]string{}},
+		{"+05:30", [2]int{19800, 19800}, [2]string{}},
+		{"-00:30", [2]int{-1800, -1800}, [2]string{}},
+		{"+05:30:15", [2]int{19815, 19815}, [2]string{}},
+		{"+053015", [2]int{19815, 19815}, [2]string{}},
+		{"UTC+2", [2]int{7200, 7200}, [2]string{}},
+		{"GMT-03:30", [2]int{-12600, -12600}, [2]string{}},
+		{"UTC-2", [2]int{-7200, -7200}, [2]string{}},
+		{"<+0330>-3:30", [2]int{12600, 12600}, [2]string{"+0330", "+0330"}},
+		{"EST5EDT,M3.2.0,M11.1.0", [2]int{-5 * 3600, -4 * 3600}, [2]string{"EST", "EDT"}},
+		{"CET-1CEST,M3.5.0,M10.5.0/3", [2]int{3600, 7200}, [2]string{"CET", "CEST"}},
+		{"AEST-10AEDT,M10.1.0,M4.1.0/3", [2]int{11 * 3600, 10 * 3600}, [2]string{"AEDT", "AEST"}},
+		{"XST3XDT", [2]int{-3 * 3600, -2 * 3600}, [2]string{"XST", "XDT"}},
+		{"EET", [2]int{7200, 7200}, [2]string{"EET", "EET"}},
+	}
+
+	for _, tt := range tests {
+		var l Loc
+		if err := l.Set([]byte(tt.in)); err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		for i := range at {
+			name, off := at[i].In(l.Get()).Zone()
+			if off != tt.off[i] || tt.name[i] != "" && name != tt.name[i] {
+				t.Errorf("%q at %v: got %s %d, want %s %d", tt.in, at[i], name, off, tt.name[i], tt.off[i])
+			}
+		}
+	}
+
+	bad := []string{
+		"", "+", "-", "+123456789", "+05:", "+05:3", "+0a", "+25", "+05:60", "+24:59", "+2459", "-24:00:01",
+		"EST5EDT,M3.2.0", "EST5EDT,M13.2.0,M11.1.0", "EST5EDT,M3.2.0,M11.1.7",
+		"E5", "<+3>-3", "EST5EDT,J0,J10", "EST5ED1", "JST-9",
+	}
+	for _, in := range bad {
+		var l Loc
+		if err := l.Set([]byte(in)); err == nil {
+			t.Errorf("%q: expected error, got %v", in, l.Get())
+		}
+	}
+}
+
+func TestFromFormatPosixTZ(t *testing.T) {
+	got, err := FromFormat([]byte("06-10 12:00 EST5EDT,M3.2.0,M11.1.0"), []byte("MM-DD hh:mm LLLLLLLLLLLLLLLLLLLLLL"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2013, time.June, 10, 16, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// arithmetic across DST keeps rules of zone
+	if _, off := got.AddDate(0, 6, 0).Zone(); off != -5*3600 {
+		t.Errorf("offset in December %d", off)
+	}
+}
+
+func TestLookupLocSet(t *testing.T) {
+	at := time.Date(2013, time.January, 10, 12, 0, 0, 0, time.UTC)
+	var z ZoneRegistry
+	for _, in := range []string{"Z", "+05", "+0530", "-03:30", "UTC-2", "UTC+2", "GMT+05:30", "EST5EDT,M3.2.0,M11.1.0", "<+0330>-3:30", "CEST"} {
+		var l Loc
+		if err := l.SetIn([]byte(in), &z); err != nil {
+			t.Errorf("%q: Set: %v", in, err)
+			continue
+		}
+		zl, err := z.Lookup(in)
+		if err != nil {
+			t.Errorf("%q: Lookup: %v", in, err)
+			continue
+		}
+		_, so := at.In(l.Get()).Zone()
+		_, lo := at.In(zl).Zone()
+		if so != lo {
+			t.Errorf("%q: Set offset %d, Lookup offset %d", in, so, lo)
+		}
+	}
+}
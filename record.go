@@ -0,0 +1,136 @@
+package yy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var errMissingField = errors.New("missing field")
+
+// Field is date field of record (message of ISO 8583, SWIFT, EDI)
+type Field struct {
+	Name   string
+	Layout Layout
+
+	// Ref is name of field, which is reference of this field,
+	// empty is reference time of record (message header).
+	Ref string
+}
+
+// FieldValue is resolved field of record
+type FieldValue struct {
+	Name string
+	Time time.Time
+	Err  error // error of field, or of its reference
+}
+
+// Record resolves date fields of record, each with reference of other field
+// ("MMDD" local transaction date relative to transmission date and time).
+// Zero value is record without fields.
+type Record struct {
+	// Resolver is policy of resolution, if nil zero Resolver is used.
+	Resolver *Resolver
+
+	Fields []Field
+}
+
+// order returns indexes of fields in dependency order,
+// and error for cycle or reference to unknown field
+func (rec *Record) order() ([]int, error) {
+	index := make(map[string]int, len(rec.Fields))
+	for i, f := range rec.Fields {
+		if _, ok := index[f.Name]; ok {
+			return nil, fmt.Errorf("yy: duplicate date field %s", f.Name)
+		}
+		index[f.Name] = i
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make([]int, len(rec.Fields))
+	var order []int
+	var path []string
+	var visit func(i int) error
+	visit = func(i int) error {
+		f := &rec.Fields[i]
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			for k, n := range path {
+				if n == f.Name {
+					path = append(path[k:], f.Name)
+					break
+				}
+			}
+			return fmt.Errorf("yy: cycle in date fields %s", strings.Join(path, " -> "))
+		}
+		state[i] = visiting
+		path = append(path, f.Name)
+		if f.Ref != "" {
+			j, ok := index[f.Ref]
+			if !ok {
+				return fmt.Errorf("yy: date field %s: unknown reference %s", f.Name, f.Ref)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = done
+		order = append(order, i)
+		return nil
+	}
+	for i := range rec.Fields {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Resolve parses fields from data (by name of field), and resolves them in dependency order,
+// each with its reference field, or with reference time rt.
+// Returns values in order of Fields, with per-field errors,
+// field with invalid reference field is not resolved.
+// Error is returned for cycle in references, or reference to unknown field.
+func (rec *Record) Resolve(data map[string]string, rt time.Time) ([]FieldValue, error) {
+	order, err := rec.order()
+	if err != nil {
+		return nil, err
+	}
+	r := rec.Resolver
+	if r == nil {
+		r = &Resolver{}
+	}
+
+	vals := make([]FieldValue, len(rec.Fields))
+	byName := make(map[string]*FieldValue, len(rec.Fields))
+	for _, i := range order {
+		f := &rec.Fields[i]
+		v := &vals[i]
+		v.Name = f.Name
+		byName[f.Name] = v
+
+		ref := rt
+		if f.Ref != "" {
+			rv := byName[f.Ref]
+			if rv.Err != nil {
+				v.Err = fmt.Errorf("reference %s: %v", f.Ref, rv.Err)
+				continue
+			}
+			ref = rv.Time
+		}
+		d, ok := data[f.Name]
+		if !ok {
+			v.Err = errMissingField
+			continue
+		}
+		v.Time, v.Err = r.Parse(f.Layout, d, ref)
+	}
+	return vals, nil
+}
@@ -0,0 +1,53 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	// ISO 8583: transmission date & time (7), local transaction date (13),
+	// settlement date (15) and expiration date (14)
+	rec := Record{Fields: []Field{
+		{Name: "14", Layout: MustCompile("YYMM"), Ref: "13"},
+		{Name: "15", Layout: MustCompile("MMDD"), Ref: "13"},
+		{Name: "13", Layout: MustCompile("MMDD"), Ref: "7"},
+		{Name: "7", Layout: MustCompile("MMDDhhmmss")},
+	}}
+	header := time.Date(2014, time.January, 1, 0, 10, 0, 0, time.UTC)
+	vals, err := rec.Resolve(map[string]string{"7": "1231235930", "13": "1231", "15": "0102", "14": "1512"}, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2015, time.December, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2014, time.January, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2013, time.December, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2013, time.December, 31, 23, 59, 30, 0, time.UTC),
+	}
+	for i, w := range want {
+		if v := vals[i]; v.Err != nil || !v.Time.Equal(w) || v.Name != rec.Fields[i].Name {
+			t.Errorf("%s: got %v %v, want %v", v.Name, v.Time, v.Err, w)
+		}
+	}
+
+	// errors of fields and their references
+	vals, err = rec.Resolve(map[string]string{"7": "1231235930", "13": "1332", "15": "0102"}, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, bad := range []bool{true, true, true, false} {
+		if (vals[i].Err != nil) != bad {
+			t.Errorf("%s: error %v", vals[i].Name, vals[i].Err)
+		}
+	}
+
+	rec.Fields[3].Ref = "15"
+	if _, err := rec.Resolve(nil, header); err == nil || err.Error() != "yy: cycle in date fields 13 -> 7 -> 15 -> 13" {
+		t.Errorf("cycle: %v", err)
+	}
+	rec.Fields[3].Ref = "12"
+	if _, err := rec.Resolve(nil, header); err == nil {
+		t.Errorf("unknown reference resolved")
+	}
+}
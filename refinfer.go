@@ -0,0 +1,148 @@
+package yy
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var errNoRef = errors.New("no reference resolves batch")
+
+// RefPolicy is policy for InferRef
+type RefPolicy struct {
+	// Min and Max are limits of plausible dates, and of reference.
+	// Zero Max is current time, zero Min is 100 years before Max.
+	Min, Max time.Time
+
+	// Monotonic declares, that batch is in ascending order.
+	Monotonic bool
+
+	// Step is step of tried references, zero is 30 days.
+	Step time.Duration
+}
+
+// RefEstimate is reference inferred by InferRef, and batch resolved with it
+type RefEstimate struct {
+	Ref        time.Time   // best reference
+	From, To   time.Time   // window of tried references, which resolve batch as Ref
+	Confidence float64     // 0..1
+	Times      []time.Time // resolved batch, zero for dates not resolved
+	Errs       []error     // errors of dates not resolved
+}
+
+// refScore is consistency of batch resolved with ref
+type refScore struct {
+	ref   time.Time
+	times []time.Time
+	errs  []error
+	good  float64 // fraction of resolved, plausible and ordered dates
+	score float64 // good lowered by mean distance from ref
+}
+
+// resolveBatch resolves batch with reference rt and scores it by policy
+func (r *Resolver) resolveBatch(batch []IDate, rt time.Time, pol *RefPolicy) refScore {
+	s := refScore{ref: rt, times: make([]time.Time, len(batch)), errs: make([]error, len(batch))}
+	var prev time.Time
+	var dist float64 // hours
+	n, good := 0, 0
+	for i := range batch {
+		t, err := r.Convert(rt, &batch[i])
+		if err != nil {
+			s.errs[i] = err
+			continue
+		}
+		s.times[i] = t
+		n++
+		dist += math.Abs(t.Sub(rt).Hours())
+		if t.Before(pol.Min) || t.After(pol.Max) {
+			continue
+		}
+		if pol.Monotonic && t.Before(prev) {
+			continue
+		}
+		prev = t
+		good++
+	}
+	if n == 0 {
+		return s
+	}
+	s.good = float64(good) / float64(len(batch))
+	s.score = s.good / (1 + dist/float64(n)/(365.25*24))
+	return s
+}
+
+// sameTimes returns if a and b are same resolutions
+func sameTimes(a, b []time.Time) bool {
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// InferRef estimates reference time for batch of incomplete dates without trustworthy
+// reference ("YYMMDD" in archives), and resolves batch with it.
+//
+// References from pol.Min to pol.Max are tried, and batch resolved with each is scored
+// by fraction of dates, which are resolved, within pol.Min and pol.Max, and in
+// ascending order when pol.Monotonic, lowered by mean distance of dates from reference,
+// so best reference is in middle of clustered dates.
+// Window From, To is range of tried references, which resolve batch same as best one.
+//
+// Confidence is fraction of consistent dates with best reference, lowered by score of best
+// reference, which resolves batch differently: 1 is consistent batch with unique resolution,
+// 0 is inconsistent batch or two equally good resolutions.
+//
+// Batch is resolved with every tried reference, that is (pol.Max-pol.Min)/pol.Step
+// references (1217 with defaults) times len(batch) conversions. For large batches
+// narrow pol.Min and pol.Max, raise pol.Step, or infer reference from sample of batch.
+func (r *Resolver) InferRef(batch []IDate, pol RefPolicy) (RefEstimate, error) {
+	if pol.Max.IsZero() {
+		pol.Max = time.Now()
+	}
+	if pol.Min.IsZero() {
+		pol.Min = pol.Max.AddDate(-100, 0, 0)
+	}
+	if pol.Step <= 0 {
+		pol.Step = 30 * 24 * time.Hour
+	}
+
+	var all []refScore
+	best := -1
+	for rt := pol.Min; !rt.After(pol.Max); rt = rt.Add(pol.Step) {
+		s := r.resolveBatch(batch, rt, &pol)
+		if s.good == 0 {
+			continue
+		}
+		all = append(all, s)
+		if best < 0 || s.score > all[best].score {
+			best = len(all) - 1
+		}
+	}
+	if best < 0 {
+		return RefEstimate{}, errNoRef
+	}
+
+	b := all[best]
+	e := RefEstimate{Ref: b.ref, From: b.ref, To: b.ref, Times: b.times, Errs: b.errs}
+	alt := 0.0
+	for _, s := range all {
+		switch {
+		case !sameTimes(s.times, b.times):
+			alt = math.Max(alt, s.score)
+		case s.ref.Before(e.From):
+			e.From = s.ref
+		case s.ref.After(e.To):
+			e.To = s.ref
+		}
+	}
+	e.Confidence = b.good * (1 - alt/b.score)
+	return e, nil
+}
+
+// InferRef estimates reference time for batch, like Resolver.InferRef
+func InferRef(batch []IDate, pol RefPolicy) (RefEstimate, error) {
+	var r Resolver
+	return r.InferRef(batch, pol)
+}
@@ -0,0 +1,63 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func parseBatch(t *testing.T, format string, data ...string) []IDate {
+	var r Resolver
+	b := make([]IDate, len(data))
+	for i, d := range data {
+		var err error
+		if b[i], err = r.ParseFormat([]byte(d), []byte(format)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b
+}
+
+func TestInferRef(t *testing.T) {
+	pol := RefPolicy{
+		Min:       time.Date(1950, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Max:       time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC),
+		Monotonic: true,
+	}
+	b := parseBatch(t, "YYMMDD", "950301", "970615", "991231", "010405", "030228")
+	e, err := InferRef(b, pol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(1995, time.March, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1997, time.June, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2001, time.April, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2003, time.February, 28, 0, 0, 0, 0, time.UTC),
+	}
+	for i, w := range want {
+		if !e.Times[i].Equal(w) {
+			t.Errorf("%d: got %v, want %v", i, e.Times[i], w)
+		}
+	}
+	if e.Ref.Before(e.From) || e.Ref.After(e.To) || e.From.Year() > 1954 || e.To.Year() < 2013 {
+		t.Errorf("window %v - %v, ref %v", e.From, e.To, e.Ref)
+	}
+	if e.Confidence < 0.9 {
+		t.Errorf("confidence %v", e.Confidence)
+	}
+
+	// month and day only, resolutions years apart are equally consistent
+	b = parseBatch(t, "MMDD", "0301", "0615")
+	if e, err = InferRef(b, pol); err != nil {
+		t.Fatal(err)
+	}
+	if e.Confidence > 0.1 {
+		t.Errorf("MMDD: confidence %v", e.Confidence)
+	}
+
+	b = parseBatch(t, "YYMMDD", "000230")
+	if _, err = InferRef(b, pol); err == nil {
+		t.Errorf("invalid date resolved")
+	}
+}
@@ -0,0 +1,417 @@
+package yy
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+var (
+	errInvalidRel    = errors.New("invalid relative offset")
+	errRelRange      = errors.New("relative offset out of range")
+	errNoBusinessDay = errors.New("no business day within year")
+)
+
+// Limits of Period components, hours, minutes and seconds together fit in time.Duration
+const (
+	maxRelYears        = 10000
+	maxRelBusinessDays = 100000
+	maxRelHours        = 800000
+	maxNonBusinessDays = 366 // consecutive days, which are not business days
+)
+
+// Period is amount of time in calendar units
+type Period struct {
+	Years, Months, Weeks, Days, BusinessDays int
+	Hours, Minutes, Seconds                  int
+}
+
+// clock returns if period have time part (hours, minutes or seconds)
+func (p Period) clock() bool {
+	return p.Hours != 0 || p.Minutes != 0 || p.Seconds != 0
+}
+
+// valid returns if components of p are within limits
+func (p Period) valid() bool {
+	in := func(v, max int) bool {
+		return v >= -max && v <= max
+	}
+	return in(p.Years, maxRelYears) && in(p.Months, 12*maxRelYears) &&
+		in(p.Weeks, 53*maxRelYears) && in(p.Days, 366*maxRelYears) &&
+		in(p.BusinessDays, maxRelBusinessDays) && in(p.Hours, maxRelHours) &&
+		in(p.Minutes, 60*maxRelHours) && in(p.Seconds, 3600*maxRelHours)
+}
+
+// neg returns negated period
+func (p Period) neg() Period {
+	return Period{
+		Years: -p.Years, Months: -p.Months, Weeks: -p.Weeks, Days: -p.Days, BusinessDays: -p.BusinessDays,
+		Hours: -p.Hours, Minutes: -p.Minutes, Seconds: -p.Seconds,
+	}
+}
+
+// AddTo returns t shifted by period p, Monday to Friday are business days.
+//
+// Years and months are added first, keeping day of month, but clamped to
+// last day of resulting month (2013-01-31 + 1 month is 2013-02-28,
+// not 2013-03-03 as with time.AddDate).
+// Then weeks and days are added, then business days (when t is not business day,
+// first business day counts as 1), and at last hours, minutes and seconds as duration.
+//
+// Error is returned for period out of limits: 10000 years (in years, months, weeks or days),
+// 100000 business days and 800000 hours (in hours, minutes or seconds).
+func (p Period) AddTo(t time.Time) (time.Time, error) {
+	return p.add(t, nil)
+}
+
+func isWeekday(t time.Time) bool {
+	wd := t.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// add returns t shifted by p, bd reports business days, nil is Monday to Friday
+func (p Period) add(t time.Time, bd func(time.Time) bool) (time.Time, error) {
+	if !p.valid() {
+		return t, errRelRange
+	}
+	if p.Years != 0 || p.Months != 0 {
+		t = addMonths(t, p.Years*12+p.Months)
+	}
+	if p.Weeks != 0 || p.Days != 0 {
+		t = t.AddDate(0, 0, p.Weeks*7+p.Days)
+	}
+	if p.BusinessDays != 0 {
+		var err error
+		if t, err = addBusinessDays(t, p.BusinessDays, bd); err != nil {
+			return t, err
+		}
+	}
+	return t.Add(time.Duration(p.Hours)*time.Hour + time.Duration(p.Minutes)*time.Minute + time.Duration(p.Seconds)*time.Second), nil
+}
+
+// daysIn returns number of days in month
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// addMonths adds n months to t, clamping day to end of month
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	hh, mm, ss := t.Clock()
+
+	mi := int(m) - 1 + n
+	y += mi / 12
+	mi %= 12
+	if mi < 0 {
+		mi += 12
+		y--
+	}
+	m = time.Month(mi + 1)
+	if last := daysIn(y, m); d > last {
+		d = last
+	}
+	return time.Date(y, m, d, hh, mm, ss, t.Nanosecond(), t.Location())
+}
+
+// addBusinessDays moves t by n business days, bd nil is Monday to Friday.
+// Error is returned after maxNonBusinessDays consecutive days, which are not business days.
+func addBusinessDays(t time.Time, n int, bd func(time.Time) bool) (time.Time, error) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	if bd == nil {
+		// any 7 consecutive days have 5 weekdays
+		if k := (n - 1) / 5; k > 0 {
+			t = t.AddDate(0, 0, 7*k*step)
+			n -= 5 * k
+		}
+		bd = isWeekday
+	}
+	skipped := 0
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if bd(t) {
+			n--
+			skipped = 0
+		} else if skipped++; skipped > maxNonBusinessDays {
+			return t, errNoBusinessDay
+		}
+	}
+	return t, nil
+}
+
+////////////////////////////////////////////////////////////
+
+// Rel indicate if relative offset present/absent in incomplete date.
+// Offset is Period relative to reference time, optionally followed
+// by move to start or end of period (end of month), to weekday (next Friday)
+// and to business day (last business day of month), in that order.
+type Rel struct {
+	present bool
+	p       Period
+
+	edge Unit // move to start/end of week, month, quarter or year
+	end  bool
+
+	wdSet bool
+	wd    time.Weekday
+	wdN   int // n-th weekday after (n > 0) or before (n < 0), 0 is weekday in same week
+
+	snap int // move forward (1) or backward (-1) to business day
+}
+
+// Unit is calendar unit, used in natural language expressions
+type Unit int
+
+// Calendar units
+const (
+	UnitHour Unit = iota + 1
+	UnitDay
+	UnitWeek
+	UnitMonth
+	UnitQuarter
+	UnitYear
+	UnitBusinessDay
+)
+
+// period returns period of n units
+func (u Unit) period(n int) (Period, bool) {
+	var p Period
+	switch u {
+	case UnitHour:
+		p.Hours = n
+	case UnitDay:
+		p.Days = n
+	case UnitWeek:
+		p.Weeks = n
+	case UnitMonth:
+		p.Months = n
+	case UnitQuarter:
+		p.Months = 3 * n
+	case UnitYear:
+		p.Years = n
+	case UnitBusinessDay:
+		p.BusinessDays = n
+	default:
+		return p, false
+	}
+	return p, true
+}
+
+// edge returns start or end of week (Monday to Sunday), month, quarter or year, containing t
+func (u Unit) edge(t time.Time, end bool) time.Time {
+	y, m, d := t.Date()
+	switch u {
+	case UnitWeek:
+		d -= (int(t.Weekday()) + 6) % 7
+		if end {
+			d += 6
+		}
+	case UnitMonth:
+		d = 1
+		if end {
+			d = daysIn(y, m)
+		}
+	case UnitQuarter:
+		m = (m-1)/3*3 + 1
+		d = 1
+		if end {
+			m += 2
+			d = daysIn(y, m)
+		}
+	case UnitYear:
+		m, d = time.January, 1
+		if end {
+			m, d = time.December, 31
+		}
+	}
+	hh, mm, ss := t.Clock()
+	return time.Date(y, m, d, hh, mm, ss, t.Nanosecond(), t.Location())
+}
+
+// weekday returns n-th weekday wd after (n > 0) or before (n < 0) t,
+// for n = 0 weekday wd in same week (Monday to Sunday) as t
+func weekday(t time.Time, wd time.Weekday, n int) time.Time {
+	cur := int(t.Weekday())
+	var d int
+	switch {
+	case n > 0:
+		d = (int(wd)-cur+6)%7 + 1 + (n-1)*7
+	case n < 0:
+		d = -((cur-int(wd)+6)%7 + 1) + (n+1)*7
+	default:
+		d = (int(wd)+6)%7 - (cur+6)%7
+	}
+	return t.AddDate(0, 0, d)
+}
+
+// apply returns t shifted by relative offset, bd nil is Monday to Friday
+func (r *Rel) apply(t time.Time, bd func(time.Time) bool) (time.Time, error) {
+	t, err := r.p.add(t, bd)
+	if err != nil {
+		return t, err
+	}
+	if r.edge != 0 {
+		t = r.edge.edge(t, r.end)
+	}
+	if r.wdSet {
+		t = weekday(t, r.wd, r.wdN)
+	}
+	if bd == nil {
+		bd = isWeekday
+	}
+	for i := 0; r.snap != 0 && !bd(t); i++ {
+		if i == maxNonBusinessDays {
+			return t, errNoBusinessDay
+		}
+		t = t.AddDate(0, 0, r.snap)
+	}
+	return t, nil
+}
+
+// SetI sets offset to v days
+func (r *Rel) SetI(v int) {
+	r.SetP(Period{Days: v})
+}
+
+// SetP sets offset to period p
+func (r *Rel) SetP(p Period) {
+	*r = Rel{present: true, p: p}
+}
+
+// Set sets offset from chars in v, accept:
+//
+//	[+-]?\d+         days
+//	[+-]?\d+[hdwmyb] hours, days, weeks, months, years or business days, unit is case insensitive
+//	[+-]?P...        ISO 8601 duration, e.g. P3M, -P10D, PT36H, P1Y2M10DT2H30M
+func (r *Rel) Set(v []byte) error {
+	s := string(asciiDigits(bytes.TrimSpace(v)))
+	neg := false
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if len(s) > 0 && (s[0] == 'P' || s[0] == 'p') {
+		p, ok := parseISODuration(s[1:])
+		if !ok {
+			return errInvalidRel
+		}
+		if neg {
+			p = p.neg()
+		}
+		if !p.valid() {
+			return errRelRange
+		}
+		r.SetP(p)
+		return nil
+	}
+
+	n, rest, ok := parseNum(s, 18)
+	if !ok || len(rest) > 1 {
+		return errInvalidRel
+	}
+	if neg {
+		n = -n
+	}
+	var p Period
+	if len(rest) == 0 {
+		p.Days = n
+	} else if !setUnit(&p, rest[0], n, false) {
+		return errInvalidRel
+	}
+	if !p.valid() {
+		return errRelRange
+	}
+	r.SetP(p)
+	return nil
+}
+
+// setUnit sets n units of unit u in p.
+// In ISO durations time part M is minutes, otherwise m & M are months.
+func setUnit(p *Period, u byte, n int, isoTime bool) bool {
+	if isoTime {
+		switch u {
+		case 'H', 'h':
+			p.Hours = n
+		case 'M', 'm':
+			p.Minutes = n
+		case 'S', 's':
+			p.Seconds = n
+		default:
+			return false
+		}
+		return true
+	}
+	switch u {
+	case 'H', 'h':
+		p.Hours = n
+	case 'D', 'd':
+		p.Days = n
+	case 'W', 'w':
+		p.Weeks = n
+	case 'M', 'm':
+		p.Months = n
+	case 'Y', 'y':
+		p.Years = n
+	case 'B', 'b':
+		p.BusinessDays = n
+	default:
+		return false
+	}
+	return true
+}
+
+// parseISODuration parses ISO 8601 duration after 'P': nYnMnWnDTnHnMnS
+func parseISODuration(s string) (Period, bool) {
+	var p Period
+	isoTime, seen := false, false
+	order := "YMWD"
+	for len(s) > 0 {
+		if s[0] == 'T' || s[0] == 't' {
+			if isoTime || len(s) == 1 {
+				return p, false
+			}
+			isoTime = true
+			order = "HMS"
+			s = s[1:]
+			continue
+		}
+		n, rest, ok := parseNum(s, 18)
+		if !ok || len(rest) == 0 {
+			return p, false
+		}
+		u := rest[0] &^ 0x20 // upper case
+		// units must be in order, without repetition
+		i := 0
+		for i < len(order) && order[i] != u {
+			i++
+		}
+		if i == len(order) {
+			return p, false
+		}
+		order = order[i+1:]
+		if !setUnit(&p, u, n, isoTime) {
+			return p, false
+		}
+		seen = true
+		s = rest[1:]
+	}
+	return p, seen
+}
+
+// Present returns presence of relative offset
+func (r *Rel) Present() bool {
+	return r.present
+}
+
+// Get returns relative offset in days, from weeks and days of it.
+// Months, years, business days and time of offset are not included, see Period.
+func (r *Rel) Get() int {
+	return r.p.Weeks*7 + r.p.Days
+}
+
+// Period returns relative offset
+func (r *Rel) Period() Period {
+	return r.p
+}
@@ -0,0 +1,142 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelSet(t *testing.T) {
+	tests := []struct {
+		in string
+		p  Period
+	}{
+		{"3", Period{Days: 3}},
+		{"-22", Period{Days: -22}},
+		{"+3h", Period{Hours: 3}},
+		{"-2w", Period{Weeks: -2}},
+		{"3M", Period{Months: 3}},
+		{"1y", Period{Years: 1}},
+		{"5b", Period{BusinessDays: 5}},
+		{"P3M", Period{Months: 3}},
+		{"-P10D", Period{Days: -10}},
+		{"PT36H", Period{Hours: 36}},
+		{"P2W", Period{Weeks: 2}},
+		{"P1Y2M10DT2H30M5S", Period{Years: 1, Months: 2, Days: 10, Hours: 2, Minutes: 30, Seconds: 5}},
+		{"-PT90M", Period{Minutes: -90}},
+	}
+	for _, tt := range tests {
+		var r Rel
+		if err := r.Set([]byte(tt.in)); err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if r.Period() != tt.p {
+			t.Errorf("%s: got %+v, want %+v", tt.in, r.Period(), tt.p)
+		}
+	}
+
+	for _, in := range []string{"", "+", "3x", "3dd", "P", "PT", "P3", "P1D2M", "P1DD", "PT1D", "P1H", "P1.5D", "--3"} {
+		var r Rel
+		if err := r.Set([]byte(in)); err == nil {
+			t.Errorf("%q: expected error, got %+v", in, r.Period())
+		}
+	}
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	d := func(y int, m time.Month, dd int) time.Time { return time.Date(y, m, dd, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		t   time.Time
+		p   Period
+		out time.Time
+	}{
+		{d(2013, 1, 31), Period{Months: 1}, d(2013, 2, 28)},
+		{d(2012, 1, 31), Period{Months: 1}, d(2012, 2, 29)},
+		{d(2012, 2, 29), Period{Years: 1}, d(2013, 2, 28)},
+		{d(2013, 3, 31), Period{Months: -1}, d(2013, 2, 28)},
+		{d(2013, 1, 31), Period{Months: -13}, d(2011, 12, 31)},
+		{d(2013, 5, 31), Period{Months: 1, Days: 1}, d(2013, 7, 1)},
+		{d(2013, 6, 7), Period{BusinessDays: 1}, d(2013, 6, 10)},   // Friday -> Monday
+		{d(2013, 6, 8), Period{BusinessDays: 1}, d(2013, 6, 10)},   // Saturday -> Monday
+		{d(2013, 6, 10), Period{BusinessDays: -1}, d(2013, 6, 7)},  // Monday -> Friday
+		{d(2013, 6, 10), Period{BusinessDays: 10}, d(2013, 6, 24)}, // two weeks
+		{d(2013, 6, 10), Period{Hours: 36}, time.Date(2013, 6, 11, 12, 0, 0, 0, time.UTC)},
+		{d(2013, 6, 8), Period{BusinessDays: 11}, d(2013, 6, 24)},      // Saturday, two weeks and day
+		{d(2013, 6, 9), Period{BusinessDays: -6}, d(2013, 5, 31)},      // Sunday, week and day back
+		{d(2013, 6, 10), Period{BusinessDays: 100000}, d(2396, 9, 30)}, // limit
+	}
+	for _, tt := range tests {
+		if got, err := tt.p.AddTo(tt.t); err != nil || !got.Equal(tt.out) {
+			t.Errorf("%v + %+v: got %v %v, want %v", tt.t, tt.p, got, err, tt.out)
+		}
+	}
+
+	for _, p := range []Period{{Hours: maxRelHours + 1}, {Years: -maxRelYears - 1}, {BusinessDays: maxRelBusinessDays + 1}} {
+		if _, err := p.AddTo(d(2013, 6, 10)); err == nil {
+			t.Errorf("%+v: out of range added", p)
+		}
+	}
+}
+
+func TestRelLimits(t *testing.T) {
+	for _, in := range []string{"+999999999999999999b", "+9999999999999h", "-99999999y", "P99999999999D"} {
+		var r Rel
+		if err := r.Set([]byte(in)); err == nil {
+			t.Errorf("%s: out of range set", in)
+		}
+	}
+
+	// business day, which never comes
+	never := Resolver{BusinessDay: func(time.Time) bool { return false }}
+	for _, in := range []string{"+1b", "0B"} {
+		var p IDate
+		if err := p.R.Set([]byte(in)); err != nil {
+			t.Fatal(err)
+		}
+		p.R.snap = 1
+		if _, err := never.Convert(ref, &p); err == nil {
+			t.Errorf("%s: resolved without business day", in)
+		}
+	}
+}
+
+func TestFromFormatRel(t *testing.T) {
+	tests := []struct {
+		in, fmt string
+		out     time.Time
+	}{
+		{"-22", "RRR", time.Date(2013, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"+3M", "RRU", time.Date(2013, 9, 10, 0, 0, 0, 0, time.UTC)},
+		{"-1y", "RRU", time.Date(2012, 6, 10, 0, 0, 0, 0, time.UTC)},
+		{"w2", "UR", time.Date(2013, 6, 24, 0, 0, 0, 0, time.UTC)},
+		{"+5b", "RRU", time.Date(2013, 6, 17, 0, 0, 0, 0, time.UTC)},
+		{"P3M", "RRR", time.Date(2013, 9, 10, 0, 0, 0, 0, time.UTC)},
+		{"-P10D", "RRRRR", time.Date(2013, 5, 31, 0, 0, 0, 0, time.UTC)},
+		// time part of offset keeps clock of reference
+		{"PT36H", "RRRRR", time.Date(2013, 6, 12, 11, 1, 2, 3, time.UTC)},
+		{"+3h 10:30", "RRU hh:mm", time.Date(2013, 6, 11, 10, 30, 0, 0, time.UTC)},
+	}
+	ext := Resolver{FormatExt: true}
+	for _, tt := range tests {
+		got, err := ext.FromFormat([]byte(tt.in), []byte(tt.fmt), ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+	}
+
+	// custom business days
+	r := Resolver{FormatExt: true, BusinessDay: func(t time.Time) bool {
+		return isWeekday(t) && !(t.Month() == time.June && t.Day() == 11)
+	}}
+	got, err := r.FromFormat([]byte("+1b"), []byte("RRU"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2013, 6, 12, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
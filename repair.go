@@ -0,0 +1,69 @@
+package yy
+
+import (
+	"fmt"
+	"time"
+)
+
+// Repair is policy for repairing invalid day of month
+type Repair uint8
+
+// Repairs, can be combined. If both RepairClamp and RepairRollover are set, day is clamped.
+const (
+	RepairClamp    Repair = 1 << iota // clamp day to last day of month: 02-30 is 02-28
+	RepairRollover                    // roll over to next month: 02-30 is 03-02, 04-31 is 05-01, 03-00 is 02-28
+	RepairDayZero                     // day 00 is last day of month: 02-00 is 02-28
+)
+
+// Warning describes repair of invalid day, or choice from readings of ambiguous day and month
+type Warning struct {
+	Year   int
+	Month  time.Month
+	Day    int       // invalid day, or day of chosen reading
+	Repair Repair    // applied repair, 0 for ambiguous day and month
+	Date   time.Time // repaired date, or chosen reading
+	Other  time.Time // other reading of ambiguous day and month
+}
+
+func (w Warning) String() string {
+	if !w.Other.IsZero() {
+		return fmt.Sprintf("ambiguous day and month, %s chosen over %s", w.Date.Format("2006-01-02"), w.Other.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("invalid date %04d-%02d-%02d repaired to %s", w.Year, int(w.Month), w.Day, w.Date.Format("2006-01-02"))
+}
+
+// repair converts p with invalid day of month, according to r.Repair.
+// Year and month are resolved as for first day of month, then day is repaired.
+func (r *Resolver) repair(rt time.Time, p *IDate) (time.Time, *Warning, bool) {
+	d := p.D.Get()
+	if r.Repair == 0 || !p.D.Present() || p.J.Present() || p.R.Present() || d < 0 || d > 31 {
+		return time.Time{}, nil, false
+	}
+	q := *p
+	q.D.SetI(1)
+	q.WD = Int{}
+	t, err := r.convert(rt, &q)
+	if err != nil {
+		return time.Time{}, nil, false
+	}
+
+	y, m, _ := t.Date()
+	last := daysIn(y, m)
+	w := Warning{Year: y, Month: m, Day: d}
+	switch {
+	case d == 0 && r.Repair&RepairDayZero != 0:
+		w.Repair, d = RepairDayZero, last
+	case d > last && r.Repair&RepairClamp != 0:
+		w.Repair, d = RepairClamp, last
+	case (d > last || d == 0) && r.Repair&RepairRollover != 0:
+		w.Repair = RepairRollover
+	default:
+		return time.Time{}, nil, false
+	}
+	hh, mm, ss := t.Clock()
+	w.Date = time.Date(y, m, d, hh, mm, ss, t.Nanosecond(), t.Location())
+	if p.WD.Present() && w.Date.Weekday() != time.Weekday(p.WD.Get()) {
+		return time.Time{}, nil, false
+	}
+	return w.Date, &w, true
+}
@@ -0,0 +1,77 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		in, fmt string
+		repair  Repair
+		out     time.Time
+	}{
+		{"02-30", "MM-DD", RepairClamp, time.Date(2013, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"12-02-30", "YY-MM-DD", RepairClamp, time.Date(2012, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"04-31 10:20", "MM-DD hh:mm", RepairClamp, time.Date(2013, time.April, 30, 10, 20, 0, 0, time.UTC)},
+		{"02-30", "MM-DD", RepairRollover, time.Date(2013, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		{"04-31", "MM-DD", RepairRollover, time.Date(2013, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"03-00", "MM-DD", RepairRollover, time.Date(2013, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"02-00", "MM-DD", RepairDayZero, time.Date(2013, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"02-00", "MM-DD", RepairDayZero | RepairRollover, time.Date(2013, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"2013-02-31", "YYYY-MM-DD", RepairClamp | RepairRollover, time.Date(2013, time.February, 28, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		r := Resolver{Repair: tt.repair}
+		p, err := r.ParseFormat([]byte(tt.in), []byte(tt.fmt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Convert(ref, &p); err == nil {
+			t.Errorf("%s: valid without repair", tt.in)
+		}
+		got, warn, err := r.ConvertWarn(ref, &p)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+		if len(warn) != 1 || !warn[0].Date.Equal(tt.out) || warn[0].Day != p.D.Get() {
+			t.Errorf("%s: warnings %v", tt.in, warn)
+		}
+	}
+
+	// not repaired
+	for _, tt := range []struct {
+		in, fmt string
+		repair  Repair
+	}{
+		{"02-00", "MM-DD", RepairClamp},
+		{"02-30", "MM-DD", RepairDayZero},
+		{"02-32", "MM-DD", RepairClamp},
+		{"13-30", "MM-DD", RepairClamp},
+		{"02-30 25:00", "MM-DD hh:mm", RepairClamp},
+	} {
+		r := Resolver{Repair: tt.repair}
+		if got, err := r.FromFormat([]byte(tt.in), []byte(tt.fmt), ref); err == nil {
+			t.Errorf("%s: repaired to %v", tt.in, got)
+		}
+	}
+
+	// valid dates have no warnings
+	r := Resolver{Repair: RepairClamp}
+	p, _ := r.ParseFormat([]byte("02-28"), []byte("MM-DD"))
+	if _, warn, err := r.ConvertWarn(ref, &p); err != nil || warn != nil {
+		t.Errorf("02-28: %v %v", warn, err)
+	}
+}
+
+func TestWarningString(t *testing.T) {
+	w := Warning{Year: 2013, Month: time.February, Day: 30, Repair: RepairClamp, Date: time.Date(2013, time.February, 28, 0, 0, 0, 0, time.UTC)}
+	if s, want := w.String(), "invalid date 2013-02-30 repaired to 2013-02-28"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
@@ -0,0 +1,258 @@
+package yy
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var errNullDate = errors.New("null date")
+
+// Never is time after all others, for open-ended dates ("99/99", "999999")
+var Never = time.Date(9999, time.December, 31, 23, 59, 59, 999999999, time.UTC)
+
+// Sentinel maps special value of data, meaning "no date" or "open-ended", to result.
+type Sentinel struct {
+	// Data is special value ("000000", "0000-00-00", "99/99"),
+	// compared with data without surrounding spaces.
+	Data string
+
+	// Pattern, if not nil, matches special values instead of Data:
+	// `^[0 ]+$` is zeros of any length and padding, `^9+(/9+)*$` is "99/99" and "99/99/99".
+	Pattern *regexp.Regexp
+
+	// Time is result, zero Time means null (no date), Never means open-ended date.
+	Time time.Time
+}
+
+// NullTime is time.Time, which may be null
+type NullTime struct {
+	Time  time.Time
+	Valid bool // Valid is true if Time is not null
+}
+
+// Resolver converts incomplete dates like Convert, but with configurable policy.
+// Zero value of Resolver is ready to use.
+//
+// Unlike Convert, by default Resolver evaluates reference time in location of
+// incomplete date (when it have one), so missing year, month and day are taken
+// from reference date as seen in that location.
+// For example reference 2013-06-10 23:30 UTC is 2013-06-11 08:30 in Asia/Tokyo,
+// and "08:15 Asia/Tokyo" resolves to 2013-06-11 08:15 JST, not to previous day.
+type Resolver struct {
+	// RefZone keeps reference time in its own location,
+	// when taking missing date components, as Convert does.
+	RefZone bool
+
+	// Zones resolves timezone names, if nil DefaultZones is used.
+	Zones *ZoneRegistry
+
+	// Locale is used for month & weekday names, if nil English is used.
+	Locale *Locale
+
+	// InferZone replaces timezone given as UTC offset (e.g. "+02:00") with named zone,
+	// which have that offset at resolved time, so DST is kept in later arithmetic.
+	// Candidates are ZoneHints in order, then zones of region of Zones (ZoneRegistry.Region),
+	// then location of reference time.
+	// If none of them match, fixed zone named after offset ("+02", "+0530") is used.
+	InferZone bool
+
+	// ZoneHints are names of preferred zones for InferZone, resolved with Zones.
+	ZoneHints []string
+
+	// BusinessDay reports if day is business day, for relative offsets in business days.
+	// If nil, Monday to Friday are business days.
+	BusinessDay func(time.Time) bool
+
+	// BlankAbsent treats blank fields (all spaces) as absent,
+	// so they are taken from reference time. Otherwise blank fields are invalid.
+	BlankAbsent bool
+
+	// Sentinels are special values of data, which are not parsed,
+	// but mapped to null or to fixed time.
+	// FromFormat and Parse return error for null, use FromFormatNull and ParseNull.
+	Sentinels []Sentinel
+
+	// Repair is policy for invalid day of month ("02-30", "04-31", "00"),
+	// repaired dates are reported by ConvertWarn. Zero value rejects invalid days.
+	Repair Repair
+
+	// YearWindow, if not zero, resolves 2 digit years to window of 100 years,
+	// starting YearWindow years before reference year, instead of nearest date.
+	// 80 is rule of ICU and Java: from 80 years before to 20 years after reference.
+	YearWindow int
+
+	// FormatExt makes 'U', 'N', 'W' and 'I' fields in formats of FromFormat
+	// (unit of relative offset, month and weekday names, roman month),
+	// otherwise they are ignored as other chars. In layouts they are always fields.
+	FormatExt bool
+
+	// Select is rule for choosing from results of several layouts in ParseAny.
+	Select Select
+
+	// DayMonth is policy for day and month, which can be swapped ("03/04").
+	// Zero value uses day and month as in data, see DayMonthOrder for order of batch.
+	DayMonth DayMonth
+}
+
+func (r *Resolver) locale() *Locale {
+	if r.Locale == nil {
+		return English
+	}
+	return r.Locale
+}
+
+func (r *Resolver) zones() *ZoneRegistry {
+	if r.Zones == nil {
+		return DefaultZones
+	}
+	return r.Zones
+}
+
+// ref returns reference time as seen from location of incomplete date
+func (r *Resolver) ref(rt time.Time, p *IDate) time.Time {
+	if r.RefZone || !p.L.Present() {
+		return rt
+	}
+	return rt.In(p.L.Get())
+}
+
+// Convert IDate to time.Time, rt is reference time.
+// Missing components are handled as in Convert.
+func (r *Resolver) Convert(rt time.Time, p *IDate) (time.Time, error) {
+	t, _, err := r.ConvertWarn(rt, p)
+	return t, err
+}
+
+// ConvertWarn is like Convert, but also returns warnings for repaired components
+// and ambiguous day and month
+func (r *Resolver) ConvertWarn(rt time.Time, p *IDate) (time.Time, []Warning, error) {
+	var warn []Warning
+	if r.YearWindow != 0 && p.Y.Digits() == 2 {
+		q := *p
+		q.Y.SetDI(4, windowYear(r.ref(rt, p).Year(), r.YearWindow, p.Y.Get()))
+		p = &q
+	}
+	t, err := r.convert(r.ref(rt, p), p)
+	t, w, err := r.dayMonth(rt, p, t, err)
+	if w != nil {
+		warn = append(warn, *w)
+	}
+	if err != nil {
+		d, w, ok := r.repair(r.ref(rt, p), p)
+		if !ok {
+			return t, nil, err
+		}
+		t, warn, err = d, append(warn, *w), nil
+	}
+	if !r.InferZone {
+		return t, warn, nil
+	}
+	if off, ok := p.L.Offset(); ok {
+		t = t.In(r.inferZone(t, off, rt.Location()))
+	}
+	return t, warn, nil
+}
+
+// windowYear returns year ending with 2 digits yy in 100 years from ref-window
+func windowYear(ref, window, yy int) int {
+	start := ref - window
+	y := start - start%100 + yy
+	if start%100 < 0 {
+		y -= 100
+	}
+	if y < start {
+		y += 100
+	}
+	return y
+}
+
+// inferZone returns named zone with offset off at t
+func (r *Resolver) inferZone(t time.Time, off int, rl *time.Location) *time.Location {
+	for _, n := range r.ZoneHints {
+		l, err := r.zones().Lookup(n)
+		if err != nil {
+			continue
+		}
+		if _, o := t.In(l).Zone(); o == off {
+			return l
+		}
+	}
+	z := r.zones()
+	for _, n := range regionZones[strings.ToUpper(z.Region)] {
+		l, err := z.Lookup(n)
+		if err != nil {
+			continue
+		}
+		if _, o := t.In(l).Zone(); o == off {
+			return l
+		}
+	}
+	if _, o := t.In(rl).Zone(); o == off {
+		return rl
+	}
+	return time.FixedZone(offsetName(off), off)
+}
+
+// offsetName returns name of fixed zone as in IANA Etc zones: +02, -0330, +053015
+func offsetName(off int) string {
+	sign := byte('+')
+	if off < 0 {
+		sign = '-'
+		off = -off
+	}
+	h, m, s := off/3600, off/60%60, off%60
+	b := []byte{sign, byte('0' + h/10), byte('0' + h%10)}
+	if m != 0 || s != 0 {
+		b = append(b, byte('0'+m/10), byte('0'+m%10))
+	}
+	if s != 0 {
+		b = append(b, byte('0'+s/10), byte('0'+s%10))
+	}
+	return string(b)
+}
+
+// FromFormat converts date according to format to time.Time,
+// format is same as in FromFormat.
+// rt are reference time.
+func (r *Resolver) FromFormat(date, format []byte, rt time.Time) (time.Time, error) {
+	return notNull(r.FromFormatNull(date, format, rt))
+}
+
+// ParseFormat parses date according to format to IDate, format is same as in FromFormat
+func (r *Resolver) ParseFormat(date, format []byte) (IDate, error) {
+	return parseFormat(date, format, r)
+}
+
+// FromFormatNull is like FromFormat, but returns null for Sentinels mapped to null
+func (r *Resolver) FromFormatNull(date, format []byte, rt time.Time) (NullTime, error) {
+	if t, ok := r.sentinel(string(date)); ok {
+		return t, nil
+	}
+	p, err := parseFormat(date, format, r)
+	if err != nil {
+		return NullTime{}, err
+	}
+	t, err := r.Convert(rt, &p)
+	return NullTime{t, err == nil}, err
+}
+
+// sentinel returns result of sentinel matching data
+func (r *Resolver) sentinel(data string) (NullTime, bool) {
+	trimmed := strings.TrimSpace(data)
+	for _, s := range r.Sentinels {
+		if s.Pattern != nil && s.Pattern.MatchString(data) || s.Pattern == nil && s.Data == trimmed {
+			return NullTime{s.Time, !s.Time.IsZero()}, true
+		}
+	}
+	return NullTime{}, false
+}
+
+// notNull returns error for null time
+func notNull(t NullTime, err error) (time.Time, error) {
+	if err == nil && !t.Valid {
+		err = errNullDate
+	}
+	return t.Time, err
+}
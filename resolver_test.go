@@ -0,0 +1,272 @@
+package yy
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	l, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestResolverRefZone(t *testing.T) {
+	tokyo := mustLoad(t, "Asia/Tokyo")
+	kiri := mustLoad(t, "Pacific/Kiritimati") // +14
+	pago := mustLoad(t, "Pacific/Pago_Pago")  // -11
+
+	tests := []struct {
+		in, fmt string
+		ref     time.Time
+		out     time.Time // Resolver
+		legacy  time.Time // Convert
+	}{
+		{
+			in:     "08:15 Asia/Tokyo",
+			fmt:    "hh:mm LLLLLLLLLL",
+			ref:    time.Date(2013, time.June, 10, 23, 30, 0, 0, time.UTC),
+			out:    time.Date(2013, time.June, 11, 8, 15, 0, 0, tokyo),
+			legacy: time.Date(2013, time.June, 10, 8, 15, 0, 0, tokyo),
+		},
+		{
+			in:     "+1 Pacific/Kiritimati",
+			fmt:    "RR LLLLLLLLLLLLLLLLLL",
+			ref:    time.Date(2013, time.June, 10, 12, 0, 0, 0, time.UTC),
+			out:    time.Date(2013, time.June, 12, 0, 0, 0, 0, kiri),
+			legacy: time.Date(2013, time.June, 11, 0, 0, 0, 0, kiri),
+		},
+		{
+			in:     "-1 Pacific/Pago_Pago",
+			fmt:    "RR LLLLLLLLLLLLLLLLL",
+			ref:    time.Date(2013, time.June, 10, 1, 0, 0, 0, time.UTC),
+			out:    time.Date(2013, time.June, 8, 0, 0, 0, 0, pago),
+			legacy: time.Date(2013, time.June, 9, 0, 0, 0, 0, pago),
+		},
+		{
+			// date line: new year already started in Kiritimati
+			in:     "Pacific/Kiritimati",
+			fmt:    "LLLLLLLLLLLLLLLLLL",
+			ref:    time.Date(2013, time.December, 31, 11, 0, 0, 0, time.UTC),
+			out:    time.Date(2014, time.January, 1, 0, 0, 0, 0, kiri),
+			legacy: time.Date(2013, time.December, 31, 0, 0, 0, 0, kiri),
+		},
+		{
+			// nearest search is not affected by location of reference
+			in:     "01-01 Pacific/Kiritimati",
+			fmt:    "MM-DD LLLLLLLLLLLLLLLLLL",
+			ref:    time.Date(2013, time.December, 31, 11, 0, 0, 0, time.UTC),
+			out:    time.Date(2014, time.January, 1, 0, 0, 0, 0, kiri),
+			legacy: time.Date(2014, time.January, 1, 0, 0, 0, 0, kiri),
+		},
+		{
+			in:     "10:00",
+			fmt:    "hh:mm",
+			ref:    time.Date(2013, time.June, 10, 23, 30, 0, 0, tokyo),
+			out:    time.Date(2013, time.June, 10, 10, 0, 0, 0, tokyo),
+			legacy: time.Date(2013, time.June, 10, 10, 0, 0, 0, tokyo),
+		},
+	}
+
+	var r Resolver
+	legacy := Resolver{RefZone: true}
+	for _, tt := range tests {
+		got, err := r.FromFormat([]byte(tt.in), []byte(tt.fmt), tt.ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+
+		got, err = FromFormat([]byte(tt.in), []byte(tt.fmt), tt.ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+		} else if !got.Equal(tt.legacy) {
+			t.Errorf("%s: legacy got %v, want %v", tt.in, got, tt.legacy)
+		}
+
+		got, err = legacy.FromFormat([]byte(tt.in), []byte(tt.fmt), tt.ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+		} else if !got.Equal(tt.legacy) {
+			t.Errorf("%s: RefZone got %v, want %v", tt.in, got, tt.legacy)
+		}
+	}
+}
+
+func TestResolverInferZone(t *testing.T) {
+	sofia := mustLoad(t, "Europe/Sofia")
+	refSofia := ref.In(sofia)
+
+	tests := []struct {
+		in    string
+		hints []string
+		ref   time.Time
+		zone  string
+		off   int
+		later int // offset after 6 months
+	}{
+		{"06-10 +02:00", []string{"Europe/Berlin"}, ref, "Europe/Berlin", 7200, 3600},
+		{"12-10 +01:00", []string{"Europe/Berlin"}, ref, "Europe/Berlin", 3600, 7200},
+		{"06-10 +03:00", []string{"Europe/Berlin"}, refSofia, "Europe/Sofia", 10800, 7200},
+		{"06-10 +01:00", []string{"Europe/Berlin"}, ref, "+01", 3600, 3600},
+		{"06-10 +05:30", nil, ref, "+0530", 19800, 19800},
+		{"06-10 -03:30", []string{"Nowhere/City", "America/St_Johns"}, ref, "-0330", -12600, -12600},
+		{"12-10 -03:30", []string{"Nowhere/City", "America/St_Johns"}, ref, "America/St_Johns", -12600, -9000},
+	}
+
+	for _, tt := range tests {
+		r := Resolver{InferZone: true, ZoneHints: tt.hints}
+		got, err := r.FromFormat([]byte(tt.in), []byte("MM-DD LLLLLL"), tt.ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if got.Location().String() != tt.zone {
+			t.Errorf("%s: zone %s, want %s", tt.in, got.Location(), tt.zone)
+		}
+		if _, off := got.Zone(); off != tt.off {
+			t.Errorf("%s: offset %d, want %d", tt.in, off, tt.off)
+		}
+		if _, off := got.AddDate(0, 6, 0).Zone(); off != tt.later {
+			t.Errorf("%s: offset after 6 months %d, want %d", tt.in, off, tt.later)
+		}
+	}
+
+	// zones of region
+	for _, tt := range []struct {
+		in, region, zone string
+	}{
+		{"06-10 -05:00", "US", "America/Chicago"},
+		{"12-10 -05:00", "US", "America/New_York"},
+		{"06-10 -07:00", "US", "America/Phoenix"},
+		{"06-10 +10:00", "AU", "Australia/Sydney"},
+		{"06-10 +08:00", "au", "Australia/Perth"},
+		{"06-10 +05:00", "AU", "+05"},
+	} {
+		r := Resolver{InferZone: true, Zones: &ZoneRegistry{Region: tt.region}}
+		got, err := r.FromFormat([]byte(tt.in), []byte("MM-DD LLLLLL"), ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if got.Location().String() != tt.zone {
+			t.Errorf("%s in %s: zone %s, want %s", tt.in, tt.region, got.Location(), tt.zone)
+		}
+	}
+
+	// without InferZone offset zone is unnamed
+	var r Resolver
+	got, err := r.FromFormat([]byte("06-10 +02:00"), []byte("MM-DD LLLLLL"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Location().String() != "" {
+		t.Errorf("zone %q", got.Location())
+	}
+}
+
+func TestResolverBlankAbsent(t *testing.T) {
+	tests := []struct {
+		in, fmt string
+		out     time.Time
+	}{
+		{"  3.06.2013", "DDD.MM.YYYY", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"    .06.2013", "DDDD.MM.YYYY", time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"06-03 10:  ", "MM-DD hh:mm", time.Date(2013, time.June, 3, 10, 0, 0, 0, time.UTC)},
+		{"      ", "YYMMDD", time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{"06-03 CET   ", "MM-DD LLLLLL", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.FixedZone("CET", 3600))},
+	}
+
+	r := Resolver{BlankAbsent: true}
+	for _, tt := range tests {
+		got, err := r.FromFormat([]byte(tt.in), []byte(tt.fmt), ref)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%q: got %v, want %v", tt.in, got, tt.out)
+		}
+	}
+
+	// blank fields are invalid by default
+	if _, err := FromFormat([]byte("    .06.2013"), []byte("DDDD.MM.YYYY"), ref); err == nil {
+		t.Error("blank day: no error")
+	}
+}
+
+func TestResolverSentinels(t *testing.T) {
+	custom := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	r := Resolver{Sentinels: []Sentinel{
+		{Data: "000000"},
+		{Data: "999999", Time: Never},
+		{Data: "111111", Time: custom},
+	}}
+
+	tests := []struct {
+		in  string
+		out NullTime
+	}{
+		{"000000", NullTime{}},
+		{"999999", NullTime{Never, true}},
+		{"111111", NullTime{custom, true}},
+		{"130603", NullTime{time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC), true}},
+	}
+	l := MustCompile("YYMMDD")
+	for _, tt := range tests {
+		got, err := r.FromFormatNull([]byte(tt.in), []byte("YYMMDD"), ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+		} else if got != tt.out {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+		got, err = r.ParseNull(l, tt.in, ref)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+		} else if got != tt.out {
+			t.Errorf("%s: Parse got %v, want %v", tt.in, got, tt.out)
+		}
+	}
+
+	if _, err := r.FromFormat([]byte("000000"), []byte("YYMMDD"), ref); err == nil {
+		t.Error("null: no error")
+	}
+	if got, err := r.Parse(l, "999999", ref); err != nil || !got.Equal(Never) {
+		t.Errorf("never: got %v, %v", got, err)
+	}
+	// without sentinel
+	if _, err := (&Resolver{}).FromFormatNull([]byte("000000"), []byte("YYMMDD"), ref); err == nil {
+		t.Error("000000: no error")
+	}
+
+	// padded data and patterns
+	r = Resolver{Sentinels: []Sentinel{
+		{Data: "0000"},
+		{Pattern: regexp.MustCompile(`^[0 ]+$`)},
+		{Pattern: regexp.MustCompile(`^9+(/9+)*$`), Time: Never},
+	}}
+	for _, tt := range []struct {
+		in  string
+		out NullTime
+	}{
+		{" 0000 ", NullTime{}},
+		{"  0000", NullTime{}},
+		{"00 000", NullTime{}},
+		{"99/99", NullTime{Never, true}},
+		{"99/99/99", NullTime{Never, true}},
+	} {
+		got, err := r.ParseNull(l, tt.in, ref)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+		} else if got != tt.out {
+			t.Errorf("%q: got %v, want %v", tt.in, got, tt.out)
+		}
+	}
+	for _, in := range []string{"99/09/99", "000001", "9/"} {
+		if _, err := r.ParseNull(l, in, ref); err == nil {
+			t.Errorf("%q: no error", in)
+		}
+	}
+}
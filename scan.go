@@ -0,0 +1,176 @@
+package yy
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ScanLayouts are default layouts of Scan: ISO dates, numeric dates with '.' and '/',
+// card expiry "MM/YY" (after word of ScanCues, or when not month and day, "09/31"), month names with day ("June 3rd", "3 Jun 2013") and ordinal days ("15th")
+var ScanLayouts = []Layout{
+	MustCompile("YYYY-MM-DD"),
+	MustCompile("D{1,2}.M{1,2}.YYYY"),
+	MustCompile("M{1,2}/D{1,2}/(YYYY|YY)"),
+	MustCompile("M{1,2}/D{1,2}"),
+	MustCompile("MM/YY"),
+	MustCompile("N D{1,2}[(\\st|nd|rd|t\\h)][, YYYY]"),
+	MustCompile("D{1,2}[(\\st|nd|rd|t\\h)] N[ YYYY]"),
+	MustCompile("D{1,2}(\\st|nd|rd|t\\h)"),
+	MustCompile("N YYYY"),
+}
+
+// ScanCues are words before date, which select layout of equally long dates,
+// by source of layout ("exp 12/25" is card expiry December 2025)
+var ScanCues = map[string]string{
+	"exp":     "MM/YY",
+	"expiry":  "MM/YY",
+	"expires": "MM/YY",
+	"thru":    "MM/YY",
+}
+
+// Match is date found in text by Scan
+type Match struct {
+	Start, End int         // byte offsets of date in text
+	Date       IDate       // parsed date
+	Time       time.Time   // resolved date
+	Others     []time.Time // other readings of date, in other layouts or by DayMonth policy
+	Layout     int         // index of layout
+	Confidence float64     // 0..1
+}
+
+// wordRune returns if r is part of word
+func wordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// specificity returns how much of date is given: 1 for year, month and day
+func specificity(p *IDate) float64 {
+	v := 0.0
+	switch {
+	case p.Y.Digits() >= 4:
+		v += 0.4
+	case p.Y.Digits() > 0:
+		v += 0.2
+	}
+	if p.Mo.Present() || p.J.Present() {
+		v += 0.3
+	}
+	if p.D.Present() || p.J.Present() {
+		v += 0.3
+	}
+	return v
+}
+
+// lastWord returns word before text[i:], in lower case
+func lastWord(text string, i int) string {
+	e := i
+	for e > 0 {
+		c, n := utf8.DecodeLastRuneInString(text[:e])
+		if wordRune(c) {
+			break
+		}
+		e -= n
+	}
+	s := e
+	for s > 0 {
+		c, n := utf8.DecodeLastRuneInString(text[:s])
+		if !wordRune(c) {
+			break
+		}
+		s -= n
+	}
+	return strings.ToLower(text[s:e])
+}
+
+// scanAt returns longest date at start of text[i:],
+// of equally long dates layout with source cue is taken
+func (r *Resolver) scanAt(text string, i int, layouts []Layout, rt time.Time, cue string) (Match, bool) {
+	end := func(j int) bool {
+		c, _ := utf8.DecodeRuneInString(text[i+j:])
+		return j > 0 && (i+j == len(text) || !wordRune(c))
+	}
+
+	var best Match
+	var times []time.Time // distinct readings of best span
+	for k, l := range layouts {
+		p, n, err := l.prefix(text[i:], r, end)
+		if err != nil || n < best.End-best.Start {
+			continue
+		}
+		t, warn, err := r.ConvertWarn(rt, &p)
+		if err != nil {
+			continue
+		}
+		switch {
+		case n > best.End-best.Start:
+			best = Match{Start: i, End: i + n, Date: p, Time: t, Layout: k, Confidence: specificity(&p)}
+			times = times[:0]
+		case cue != "" && l.String() == cue:
+			best.Date, best.Time, best.Layout, best.Confidence = p, t, k, specificity(&p)
+		}
+		for _, w := range warn {
+			if !w.Other.IsZero() {
+				times = appendTime(times, w.Other)
+			}
+		}
+		times = appendTime(times, t)
+	}
+	if len(times) == 0 {
+		return best, false
+	}
+	best.Confidence /= float64(len(times))
+	for _, u := range times {
+		if !u.Equal(best.Time) {
+			best.Others = append(best.Others, u)
+		}
+	}
+	return best, true
+}
+
+// appendTime appends t to ts, if it is not in ts
+func appendTime(ts []time.Time, t time.Time) []time.Time {
+	for _, u := range ts {
+		if u.Equal(t) {
+			return ts
+		}
+	}
+	return append(ts, t)
+}
+
+// Scan finds dates in free text ("on 10/06", "due 15th", "June 3rd"), and resolves
+// them with reference time rt. Layouts are tried at start of each word,
+// and date must end at end of word, if nil ScanLayouts are used.
+// Longest date is taken, from first layout which matches it, dates do not overlap,
+// unless word before date is in ScanCues: "12/25" is December 25, but "exp 12/25" is
+// card expiry December 2025. Other readings of date are in Match.Others.
+//
+// Confidence is specificity of date (1 for year, month and day, 0.3 for day only)
+// divided by number of different readings of date in layouts and by DayMonth policy
+// ("10/06" is October 6 or October 2006).
+func (r *Resolver) Scan(text string, layouts []Layout, rt time.Time) []Match {
+	if layouts == nil {
+		layouts = ScanLayouts
+	}
+	var all []Match
+	word := false // previous rune is part of word
+	for i := 0; i < len(text); {
+		if !word {
+			if m, ok := r.scanAt(text, i, layouts, rt, ScanCues[lastWord(text, i)]); ok {
+				all = append(all, m)
+				i, word = m.End, false
+				continue
+			}
+		}
+		c, n := utf8.DecodeRuneInString(text[i:])
+		i, word = i+n, wordRune(c)
+	}
+	return all
+}
+
+// Scan finds dates in free text with ScanLayouts, like Resolver.Scan
+func Scan(text string, ref time.Time) []Match {
+	var r Resolver
+	return r.Scan(text, nil, ref)
+}
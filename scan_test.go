@@ -0,0 +1,53 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScan(t *testing.T) {
+	text := "Call on 10/06, invoice due 15th, renew 12/25, card exp 12/25, exp 09/31. Meeting June 3rd at noon, shipped 2013-05-28, 3 Jul 2012, id 110/06."
+	want := []struct {
+		text string
+		t    time.Time
+		conf float64
+	}{
+		{"10/06", time.Date(2013, time.October, 6, 0, 0, 0, 0, time.UTC), 0.3},
+		{"15th", time.Date(2013, time.June, 15, 0, 0, 0, 0, time.UTC), 0.3},
+		{"12/25", time.Date(2012, time.December, 25, 0, 0, 0, 0, time.UTC), 0.3},
+		{"12/25", time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), 0.25},
+		{"09/31", time.Date(2031, time.September, 1, 0, 0, 0, 0, time.UTC), 0.5},
+		{"June 3rd", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC), 0.6},
+		{"2013-05-28", time.Date(2013, time.May, 28, 0, 0, 0, 0, time.UTC), 1},
+		{"3 Jul 2012", time.Date(2012, time.July, 3, 0, 0, 0, 0, time.UTC), 1},
+	}
+
+	got := Scan(text, ref)
+	if len(got) != len(want) {
+		t.Fatalf("got %d dates: %v", len(got), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if s := text[g.Start:g.End]; s != w.text {
+			t.Errorf("%d: got %q, want %q", i, s, w.text)
+		}
+		if !g.Time.Equal(w.t) {
+			t.Errorf("%s: got %v, want %v", w.text, g.Time, w.t)
+		}
+		if d := g.Confidence - w.conf; d > 1e-9 || d < -1e-9 {
+			t.Errorf("%s: confidence %v, want %v", w.text, g.Confidence, w.conf)
+		}
+	}
+
+	var r Resolver
+	got = r.Scan("from 03.06.2013 to 04.06.2013", []Layout{MustCompile("DD.MM.YYYY")}, ref)
+	if len(got) != 2 || got[1].Date.D.Get() != 4 {
+		t.Errorf("custom layout: %v", got)
+	}
+
+	// other reading
+	got = Scan("renew 12/25", ref)
+	if want := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC); len(got) != 1 || len(got[0].Others) != 1 || !got[0].Others[0].Equal(want) {
+		t.Errorf("other readings: got %v, want %v", got, want)
+	}
+}
@@ -1,8 +1,11 @@
 package yy
 
 import (
+	"bytes"
 	"strconv"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Sets value from chars in []byte
@@ -10,21 +13,90 @@ type setter interface {
 	Set([]byte) error
 }
 
+// digitValue returns value of Unicode decimal digit r
+func digitValue(r rune) (int, bool) {
+	if r >= '0' && r <= '9' {
+		return int(r - '0'), true
+	}
+	if r < utf8.RuneSelf || !unicode.Is(unicode.Nd, r) {
+		return 0, false
+	}
+	// all decimal digit ranges are blocks of digits from 0 to 9
+	for _, rg := range unicode.Nd.R16 {
+		if r >= rune(rg.Lo) && r <= rune(rg.Hi) {
+			return int(r-rune(rg.Lo)) % 10, true
+		}
+	}
+	for _, rg := range unicode.Nd.R32 {
+		if r >= rune(rg.Lo) && r <= rune(rg.Hi) {
+			return int(r-rune(rg.Lo)) % 10, true
+		}
+	}
+	return 0, false
+}
+
+// asciiDigits replaces Unicode decimal digits in v with ASCII digits,
+// other chars are unchanged
+func asciiDigits(v []byte) []byte {
+	i := 0
+	for i < len(v) && v[i] < utf8.RuneSelf {
+		i++
+	}
+	if i == len(v) {
+		return v
+	}
+	r := append([]byte(nil), v[:i]...)
+	for i < len(v) {
+		c, n := utf8.DecodeRune(v[i:])
+		if d, ok := digitValue(c); ok {
+			r = append(r, byte('0'+d))
+		} else {
+			r = append(r, v[i:i+n]...)
+		}
+		i += n
+	}
+	return r
+}
+
+// atoi is strconv.Atoi, accepting Unicode decimal digits and surrounding spaces
+func atoi(v []byte) (int, error) {
+	return strconv.Atoi(string(asciiDigits(bytes.TrimSpace(v))))
+}
+
 // Loc indicate if timezone present/absent in incomplete date
 type Loc struct {
-	l *time.Location
+	l      *time.Location
+	offset bool // timezone is given as UTC offset
 }
 
-// Set sets timezone
-// accept `[+-]\d\d:{0,1}\d\d` or `.+`
+// Set sets timezone, accept:
+//
+//	Z                          UTC
+//	[+-]hh[[:]mm[[:]ss]]       UTC offset, also [+-]h and [+-]h[:]mm
+//	(UTC|GMT|UT)[+-]offset     UTC offset as above, "UTC+2" is 2 hours east of UTC
+//	.+                         timezone name or POSIX TZ string, resolved with DefaultZones
 func (t *Loc) Set(v []byte) error {
+	return t.SetIn(v, DefaultZones)
+}
+
+// SetIn sets timezone like Set, names are resolved with zone registry z
+func (t *Loc) SetIn(v []byte, z *ZoneRegistry) error {
+	v = bytes.TrimSpace(v)
+	if len(v) == 0 {
+		return errInvalidZone
+	}
+	if s, ok := offsetZone(string(asciiDigits(v))); ok {
+		return t.SetS(s)
+	}
 	if v[0] == '+' || v[0] == '-' {
-		if v[3] == ':' {
-			return t.SetHHMM(v[:3], v[4:6])
-		}
-		return t.SetHHMM(v[:3], v[3:5])
+		return errInvalidZone
+	}
+	if len(v) == 1 && v[0] == 'Z' {
+		t.l = time.UTC
+		t.offset = false
+		return nil
 	}
-	return t.SetName(v)
+	return t.setName(v, z)
 }
 
 // Present returns if timezone is present in incomplete date
@@ -39,11 +111,11 @@ func (t *Loc) Get() *time.Location {
 
 // SetHHMM sets timezone to hh:mm
 func (t *Loc) SetHHMM(hs, ms []byte) error {
-	h, err := strconv.Atoi(string(hs))
+	h, err := atoi(hs)
 	if err != nil {
 		return err
 	}
-	m, err := strconv.Atoi(string(ms))
+	m, err := atoi(ms)
 	if err != nil {
 		return err
 	}
@@ -55,33 +127,48 @@ func (t *Loc) SetHHMM(hs, ms []byte) error {
 }
 
 // SetName sets timezone from name, special names 'z' && 'l'
-// set UTC && Local timezones
+// set UTC && Local timezones.
+// Names are resolved with DefaultZones registry.
 func (t *Loc) SetName(v []byte) error {
-	// TODO trims spaces
-	zs := string(v)
-	if zs == "z" {
-		t.l = time.UTC
-		return nil
-	}
-	if zs == "l" {
-		t.l = time.Local
-		return nil
-	}
+	return t.setName(v, DefaultZones)
+}
 
-	l, err := time.LoadLocation(zs)
+func (t *Loc) setName(v []byte, z *ZoneRegistry) error {
+	l, err := z.Lookup(string(bytes.TrimSpace(v)))
 	if err != nil {
 		return err
 	}
 	t.l = l
+	t.offset = false
 	return nil
 }
 
+// zoneSetter sets Loc, resolving names with zone registry
+type zoneSetter struct {
+	l *Loc
+	z *ZoneRegistry
+}
+
+func (s zoneSetter) Set(v []byte) error {
+	return s.l.SetIn(v, s.z)
+}
+
 // SetS sets timezone from seconds offset
 func (t *Loc) SetS(s int) error {
 	t.l = time.FixedZone("", s)
+	t.offset = true
 	return nil
 }
 
+// Offset returns UTC offset in seconds, if timezone is given as offset
+func (t *Loc) Offset() (int, bool) {
+	if !t.offset {
+		return 0, false
+	}
+	_, s := time.Time{}.In(t.l).Zone()
+	return s, true
+}
+
 //////////////////////
 
 // Int indicate if various parts of date (day,month,hour,min,sec)
@@ -99,9 +186,9 @@ func (i *Int) SetI(v int) {
 	i.present = true
 }
 
-// Set sets Int from chars in v
+// Set sets Int from chars in v, surrounding spaces are ignored
 func (i *Int) Set(v []byte) error {
-	iv, err := strconv.Atoi(string(v))
+	iv, err := atoi(v)
 	if err == nil {
 		i.present = true
 		i.val = iv
@@ -126,8 +213,9 @@ type Frac struct {
 	Int
 }
 
-// Set sets Frac from chars in v
+// Set sets Frac from chars in v, surrounding spaces are ignored
 func (f *Frac) Set(v []byte) error {
+	v = asciiDigits(bytes.TrimSpace(v))
 	// force allocation of new backing array
 	return f.Int.Set(append(v[:len(v):len(v)], []byte("000000000000")...)[:9])
 }
@@ -140,15 +228,16 @@ type Year struct {
 	y      int
 }
 
-// Set sets year from chars in v, number of digits is set to len(v)
+// Set sets year from chars in v, number of digits is set to number of chars in v,
+// surrounding spaces are ignored, but counted (" 7" is year of 2 digits)
 func (y *Year) Set(v []byte) error {
 	if len(v) == 0 {
 		y.digits = 0
 		return nil
 	}
-	yv, err := strconv.Atoi(string(v))
+	yv, err := strconv.Atoi(string(asciiDigits(bytes.TrimSpace(v))))
 	if err == nil {
-		y.digits = int8(len(v))
+		y.digits = int8(utf8.RuneCount(v))
 		y.y = yv
 	}
 	return err
@@ -170,10 +259,30 @@ func (y *Year) Digits() int8 {
 	return y.digits
 }
 
+//////////////////////
+
+// WeekKind is numbering of weeks in year
+type WeekKind int8
+
+// Week numberings
+const (
+	WeekISO    WeekKind = iota // ISO 8601 week 1..53, week 1 contains January 4, year is ISO week-numbering year
+	WeekSunday                 // week 0..53, week 1 starts with first Sunday of year
+	WeekMonday                 // week 0..53, week 1 starts with first Monday of year
+)
+
+// Week indicate if week number present/absent in incomplete date
+type Week struct {
+	Int
+	Kind WeekKind
+}
+
 // IDate represent components of incomplete date
 type IDate struct {
-	R, J, Mo, D, H, M, S Int
-	F                    Frac
-	Y                    Year
-	L                    Loc
+	R                     Rel
+	J, Mo, D, H, M, S, WD Int // WD is weekday, 0 is Sunday
+	WN                    Week
+	F                     Frac
+	Y                     Year
+	L                     Loc
 }
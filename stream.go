@@ -0,0 +1,129 @@
+package yy
+
+import (
+	"fmt"
+	"time"
+)
+
+// AnomalyKind is kind of anomaly in stream
+type AnomalyKind int
+
+// Anomalies of stream
+const (
+	AnomalyRollover  AnomalyKind = iota + 1 // backwards jump, resolved to next year, month, day...
+	AnomalyBackwards                        // backwards jump of complete date, over jitter
+	AnomalyGap                              // forward jump over MaxGap
+)
+
+var anomalyNames = [...]string{AnomalyRollover: "rollover", AnomalyBackwards: "backwards jump", AnomalyGap: "gap"}
+
+// Anomaly is reported by StreamResolver for value out of order of stream
+type Anomaly struct {
+	Kind AnomalyKind
+	Prev time.Time // reference, latest of previous values
+	Time time.Time // resolved value
+}
+
+func (a Anomaly) String() string {
+	return fmt.Sprintf("%s from %s to %s", anomalyNames[a.Kind], a.Prev.Format(time.RFC3339Nano), a.Time.Format(time.RFC3339Nano))
+}
+
+// StreamResolver resolves values of stream (log file, event feed), each with
+// reference from previous values, so rollover of year, month or day inside stream
+// is handled ("Dec 31 23:59:59" followed by "Jan  1 00:00:01").
+// Zero value of StreamResolver is ready to use, with reference of current time.
+type StreamResolver struct {
+	// Resolver is policy of resolution, if nil zero Resolver is used.
+	Resolver *Resolver
+
+	// Ref is reference of next value, latest of resolved values.
+	// If zero, current time is used for first value.
+	Ref time.Time
+
+	// Jitter is tolerated out of order: values up to Jitter before Ref are accepted.
+	Jitter time.Duration
+
+	// MaxGap, if not zero, reports forward jumps over MaxGap as anomaly.
+	MaxGap time.Duration
+}
+
+// nextPeriod returns t advanced by period of missing components of p (year for date without year,
+// day for time without date), and false for complete date
+func nextPeriod(t time.Time, p *IDate) (time.Time, bool) {
+	switch {
+	case p.R.Present() || p.Y.Digits() >= 4:
+		return t, false
+	case p.Y.Digits() > 0:
+		n := 1
+		for i := int8(0); i < p.Y.Digits(); i++ {
+			n *= 10
+		}
+		return t.AddDate(n, 0, 0), true
+	case p.Mo.Present() || p.J.Present() || p.WN.Present():
+		return t.AddDate(1, 0, 0), true
+	case p.D.Present():
+		return t.AddDate(0, 1, 0), true
+	case p.WD.Present():
+		return t.AddDate(0, 0, 7), true
+	case p.H.Present():
+		return t.AddDate(0, 0, 1), true
+	case p.M.Present():
+		return t.Add(time.Hour), true
+	case p.S.Present():
+		return t.Add(time.Minute), true
+	}
+	return t, false
+}
+
+// Convert resolves p nearest to previous values, and advances reference.
+//
+// Value before reference by more than Jitter is backwards jump, and is kept as is.
+// Only when it is before reference by more than half of period of missing components
+// (half year for date without year), it is resolved to next period (rollover),
+// if that is after reference. Rollover to next year, month or day within half
+// of period is done by nearest date resolution, and is not anomaly.
+// Backwards jumps, rollovers and forward gaps over MaxGap are reported as anomaly.
+// Reference is not moved backwards.
+func (s *StreamResolver) Convert(p *IDate) (time.Time, *Anomaly, error) {
+	r := s.Resolver
+	if r == nil {
+		r = &Resolver{}
+	}
+	if s.Ref.IsZero() {
+		s.Ref = time.Now()
+	}
+
+	t, err := r.Convert(s.Ref, p)
+	if err != nil {
+		return t, nil, err
+	}
+	var a *Anomaly
+	switch {
+	case t.Before(s.Ref.Add(-s.Jitter)):
+		a = &Anomaly{Kind: AnomalyBackwards, Prev: s.Ref, Time: t}
+		if next, ok := nextPeriod(t, p); ok && s.Ref.Sub(t) > next.Sub(t)/2 {
+			if u, err := r.Convert(next, p); err == nil && !u.Before(s.Ref.Add(-s.Jitter)) {
+				t, a.Kind, a.Time = u, AnomalyRollover, u
+			}
+		}
+	case s.MaxGap != 0 && t.Sub(s.Ref) > s.MaxGap:
+		a = &Anomaly{Kind: AnomalyGap, Prev: s.Ref, Time: t}
+	}
+	if t.After(s.Ref) {
+		s.Ref = t
+	}
+	return t, a, nil
+}
+
+// Parse parses data according to layout l, and resolves it like Convert
+func (s *StreamResolver) Parse(l Layout, data string) (time.Time, *Anomaly, error) {
+	r := s.Resolver
+	if r == nil {
+		r = &Resolver{}
+	}
+	p, err := l.parse(data, r)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return s.Convert(&p)
+}
@@ -0,0 +1,68 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreamResolver(t *testing.T) {
+	syslog := MustCompile("N D{1,2>} hh:mm:ss")
+	s := StreamResolver{
+		Ref:    time.Date(2012, time.December, 31, 12, 0, 0, 0, time.UTC),
+		Jitter: time.Minute,
+		MaxGap: 24 * time.Hour,
+	}
+	tests := []struct {
+		in      string
+		out     time.Time
+		anomaly AnomalyKind
+	}{
+		{"Dec 31 23:59:58", time.Date(2012, time.December, 31, 23, 59, 58, 0, time.UTC), 0},
+		{"Jan  1 00:00:01", time.Date(2013, time.January, 1, 0, 0, 1, 0, time.UTC), 0},
+		{"Dec 31 23:59:59", time.Date(2012, time.December, 31, 23, 59, 59, 0, time.UTC), 0},
+		{"Jan  1 00:00:02", time.Date(2013, time.January, 1, 0, 0, 2, 0, time.UTC), 0},
+		{"Jan  5 10:00:00", time.Date(2013, time.January, 5, 10, 0, 0, 0, time.UTC), AnomalyGap},
+		{"Jan  5 09:00:00", time.Date(2013, time.January, 5, 9, 0, 0, 0, time.UTC), AnomalyBackwards},
+		{"Jan  5 10:00:05", time.Date(2013, time.January, 5, 10, 0, 5, 0, time.UTC), 0},
+	}
+	for _, tt := range tests {
+		got, a, err := s.Parse(syslog, tt.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.in, err)
+		}
+		if !got.Equal(tt.out) {
+			t.Errorf("%s: got %v, want %v", tt.in, got, tt.out)
+		}
+		switch {
+		case a == nil && tt.anomaly != 0:
+			t.Errorf("%s: no anomaly", tt.in)
+		case a != nil && a.Kind != tt.anomaly:
+			t.Errorf("%s: anomaly %v", tt.in, a)
+		}
+	}
+
+	// time only feed, midnight rollover after gap over half day
+	s = StreamResolver{Ref: time.Date(2013, time.June, 10, 8, 0, 0, 0, time.UTC)}
+	hm := MustCompile("hh:mm")
+	for _, tt := range []struct {
+		in  string
+		out time.Time
+	}{
+		{"10:00", time.Date(2013, time.June, 10, 10, 0, 0, 0, time.UTC)},
+		{"23:30", time.Date(2013, time.June, 10, 23, 30, 0, 0, time.UTC)},
+		{"09:00", time.Date(2013, time.June, 11, 9, 0, 0, 0, time.UTC)},
+	} {
+		if got, _, err := s.Parse(hm, tt.in); err != nil || !got.Equal(tt.out) {
+			t.Errorf("%s: got %v %v, want %v", tt.in, got, err, tt.out)
+		}
+	}
+
+	// complete dates are not rolled over
+	s = StreamResolver{Ref: time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC)}
+	_, a, _ := s.Parse(MustCompile("YYYY-MM-DD"), "2013-06-01")
+	if a == nil || a.Kind != AnomalyBackwards {
+		t.Errorf("backwards jump: %v", a)
+	} else if s := a.String(); s != "backwards jump from 2013-06-10T00:00:00Z to 2013-06-01T00:00:00Z" {
+		t.Errorf("anomaly %q", s)
+	}
+}
@@ -0,0 +1,123 @@
+package yy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// strptime directives and their layouts
+var strptimeDirectives = map[byte]string{
+	'Y': "YYYY",
+	'y': "YY",
+	'C': "CC",
+	'G': "YYYY",
+	'g': "YY",
+	'm': "M{1,2}",
+	'd': "D{1,2}",
+	'e': "D{1,2>}",
+	'j': "J{1,3}",
+	'H': "h{1,2}",
+	'k': "h{1,2>}",
+	'I': "K{1,2}",
+	'l': "K{1,2>}",
+	'M': "m{1,2}",
+	'S': "s{1,2}",
+	'f': "f{1,9}",
+	'p': "A",
+	'P': "A",
+	'a': "W",
+	'A': "W",
+	'b': "N",
+	'B': "N",
+	'h': "N",
+	'u': "E",
+	'w': "E",
+	'V': "V{1,2}",
+	'U': "X{1,2}",
+	'W': "O{1,2}",
+	'z': "(L{9}|L{6}|L{5}|L{3}|L)",
+	'Z': "L{1,}",
+	'n': " ",
+	't': " ",
+	'%': "%",
+}
+
+// strptime composite directives
+var strptimeComposites = map[byte]string{
+	'D': "%m/%d/%y",
+	'F': "%Y-%m-%d",
+	'T': "%H:%M:%S",
+	'R': "%H:%M",
+	'r': "%I:%M:%S %p",
+	'c': "%a %b %e %H:%M:%S %Y",
+	'x': "%m/%d/%y",
+	'X': "%H:%M:%S",
+}
+
+// CompileStrptime compiles strptime format ("%y%m%d", "%b %e %H:%M:%S") to Layout.
+//
+// Directives are as in C and Python strptime (POSIX locale), supported are:
+//
+//	%Y %y %C %G %g     year, 2 digit year, century, ISO week-numbering year
+//	%m %d %e %j        month, day, space padded day, day of year
+//	%H %k %I %l        hour, space padded hour, hour on 12-hour clock
+//	%M %S %f           minute, second, fraction of second (up to 9 digits)
+//	%p %P              AM or PM
+//	%a %A %b %B %h     weekday and month names, full or abbreviated
+//	%u %w              weekday number, Monday is 1, Sunday is 0 or 7
+//	%V %U %W           ISO 8601 week, week starting with Sunday, with Monday
+//	%z %Z              UTC offset ("+0200", "+02:00", "Z") and timezone name
+//	%D %F %T %R %r     composites: %m/%d/%y, %Y-%m-%d, %H:%M:%S, %H:%M, %I:%M:%S %p
+//	%c %x %X           as in POSIX locale
+//	%n %t              one space
+//	%%                 %
+//
+// Modifiers E and O (%Ey, %Od) are ignored.
+// Unlike C and Python strptime, where whitespace in format matches any run of whitespace
+// (also none), spaces in format, %n and %t match exactly one space, other whitespace
+// is literal. Use %e, %k and %l for space padded fields.
+// Truncated years (%y, %g) are resolved to nearest date, not with fixed pivot year,
+// and missing components are taken from reference time, as in Convert.
+func CompileStrptime(format string) (Layout, error) {
+	var b strings.Builder
+	if err := strptime(&b, format); err != nil {
+		return Layout{}, err
+	}
+	l, err := Compile(b.String())
+	if err != nil {
+		return Layout{}, err
+	}
+	l.src = format
+	return l, nil
+}
+
+func strptime(b *strings.Builder, format string) error {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			n := strings.IndexByte(format[i:], '%')
+			if n < 0 {
+				n = len(format) - i
+			}
+			b.WriteString(layoutEscape(format[i : i+n]))
+			i += n - 1
+			continue
+		}
+		i++
+		if i < len(format) && (format[i] == 'E' || format[i] == 'O') {
+			i++
+		}
+		if i == len(format) {
+			return fmt.Errorf("yy: strptime format %q: trailing %%", format)
+		}
+		if c, ok := strptimeComposites[format[i]]; ok {
+			strptime(b, c)
+			continue
+		}
+		d, ok := strptimeDirectives[format[i]]
+		if !ok {
+			return fmt.Errorf("yy: strptime format %q: unknown directive %%%c", format, format[i])
+		}
+		b.WriteString(d)
+	}
+	return nil
+}
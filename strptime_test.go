@@ -0,0 +1,84 @@
+package yy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStrptime(t *testing.T) {
+	sofia := mustLoad(t, "Europe/Sofia")
+	tests := []struct {
+		format, in string
+		out        time.Time
+	}{
+		{"%y%m%d", "130603", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"%y%m%d", "620603", time.Date(2062, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"%y%m%d", "650603", time.Date(1965, time.June, 3, 0, 0, 0, 0, time.UTC)}, // glibc: 2065
+		{"%b %e %H:%M:%S", "Jun  3 10:20:30", time.Date(2013, time.June, 3, 10, 20, 30, 0, time.UTC)},
+		{"%b %e %H:%M:%S", "Dec 31 10:20:30", time.Date(2012, time.December, 31, 10, 20, 30, 0, time.UTC)},
+		{"%j", "161", time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{"%Y-%j", "2012-366", time.Date(2012, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{"%C%y-%m-%d", "1999-12-31", time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{"%F %T", "2013-06-03 10:20:30", time.Date(2013, time.June, 3, 10, 20, 30, 0, time.UTC)},
+		{"%D %r", "06/03/13 10:20:30 PM", time.Date(2013, time.June, 3, 22, 20, 30, 0, time.UTC)},
+		{"%a, %d %B %Y", "Monday, 03 June 2013", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2013-W01-1", time.Date(2012, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V-%u", "2015-W53-7", time.Date(2016, time.January, 3, 0, 0, 0, 0, time.UTC)},
+		{"%G-W%V", "2013-W23", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+		{"W%V-%u", "W24-5", time.Date(2013, time.June, 14, 0, 0, 0, 0, time.UTC)},
+		{"%Y %U %w", "2013 22 0", time.Date(2013, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		{"%Y %U %w", "2013 00 2", time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"%Y %W %u", "2013 23 1", time.Date(2013, time.June, 10, 0, 0, 0, 0, time.UTC)},
+		{"%Y %W", "2013 00", time.Date(2013, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"%m-%d %H:%M %z", "06-03 10:20 +0300", time.Date(2013, time.June, 3, 7, 20, 0, 0, time.UTC)},
+		{"%m-%d %H:%M %z", "06-03 10:20 Z", time.Date(2013, time.June, 3, 10, 20, 0, 0, time.UTC)},
+		{"%m-%d %H:%M %Z", "06-03 10:20 Europe/Sofia", time.Date(2013, time.June, 3, 10, 20, 0, 0, sofia)},
+		{"%H:%M:%S.%f", "10:20:30.25", time.Date(2013, time.June, 10, 10, 20, 30, 250000000, time.UTC)},
+		{"100%% %Oy%Em", "100% 1306", time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"%d.%m. ок", "3.6. ок", time.Date(2013, time.June, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var r Resolver
+	for _, tt := range tests {
+		l, err := CompileStrptime(tt.format)
+		if err != nil {
+			t.Errorf("%s: %v", tt.format, err)
+			continue
+		}
+		got, err := r.Parse(l, tt.in, ref)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.format, tt.in, err)
+		} else if !got.Equal(tt.out) {
+			t.Errorf("%s %s: got %v, want %v", tt.format, tt.in, got, tt.out)
+		}
+	}
+
+	for _, tt := range []struct{ format, in string }{
+		{"%G-W%V-%u", "2013-W53-1"},
+		{"%G-W%V-%u", "2013-W01-8"},
+		{"%Y %U", "2013 54"},
+		{"%I:%M", "13:00"},
+		{"%C%Y", "202013"},
+		// whitespace matches exactly one space
+		{"%d %m", "10  06"},
+		{"%d%n%m", "1006"},
+	} {
+		if got, err := r.Parse(mustStrptime(t, tt.format), tt.in, ref); err == nil {
+			t.Errorf("%s %s: got %v", tt.format, tt.in, got)
+		}
+	}
+
+	for _, f := range []string{"%", "%q", "%E"} {
+		if _, err := CompileStrptime(f); err == nil {
+			t.Errorf("%s: no error", f)
+		}
+	}
+}
+
+func mustStrptime(t *testing.T, format string) Layout {
+	l, err := CompileStrptime(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
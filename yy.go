@@ -29,6 +29,8 @@
 //  Y-JJJ        find XXX such that XXXY-JJJ is nearest valid date
 //  JJJ          find XXXX such that XXXX-JJJ is nearest valid date
 //  +/-RRR       RRR days after/before today
+//  +/-RRRU      RRR units (hours, days, weeks, months, years, business days) after/before today
+//  +/-PnYnMnDTnH ISO 8601 duration after/before today
 //  YYYY-MM-DD   full date
 //  YYYY         return YYYY-01-01
 //  YYY          find X such that XYYY-01-01 is nearest valid date
@@ -43,13 +45,17 @@
 package yy
 
 import (
+	"bytes"
 	"errors"
 	"time"
+	"unicode/utf8"
 )
 
 var (
 	errInvalidDate       = errors.New("invalid date")
 	errInvalidComponents = errors.New("invalid date components")
+	errInvalidName       = errors.New("invalid month name, weekday name or roman numeral")
+	errShortDate         = errors.New("date shorter than format")
 )
 
 // Convert IDate to time.Time.
@@ -58,7 +64,15 @@ var (
 // All missing date parts(day & month), not subject to finding, defaults to 1.
 // Missing location defaults to coping location from reference time.
 // If no any date component present, converts to reference date.
+// Relative offset is added to reference time as in Period.AddTo, when offset have
+// hours, minutes or seconds, missing time parts are taken from shifted reference time.
 func Convert(rt time.Time, p *IDate) (time.Time, error) {
+	var r Resolver
+	return r.convert(rt, p)
+}
+
+// convert is Convert with policy of r, reference time is used as is
+func (r *Resolver) convert(rt time.Time, p *IDate) (time.Time, error) {
 	y, mo, dd := rt.Date()
 	var h, m, s, f int
 
@@ -86,17 +100,72 @@ func Convert(rt time.Time, p *IDate) (time.Time, error) {
 
 	var t Tm
 
+	// weekday, if present, must match
+	find := func(v dateFinder) (time.Time, error) {
+		if p.WD.Present() {
+			return nearDateFindN(rt, &weekdayFind{v, time.Weekday(p.WD.Get())}, 60, true)
+		}
+		return nearDateFind(rt, v)
+	}
+	check := func(d time.Time, err error) (time.Time, error) {
+		if err == nil && p.WD.Present() && d.Weekday() != time.Weekday(p.WD.Get()) {
+			return time.Time{}, errInvalidDate
+		}
+		return d, err
+	}
+
 	// if ! have some date   {
-	if !p.R.Present() && !p.Mo.Present() && !p.D.Present() && !p.J.Present() && p.Y.Digits() == 0 {
+	if !p.R.Present() && !p.Mo.Present() && !p.D.Present() && !p.J.Present() && !p.WN.Present() && p.Y.Digits() == 0 {
+		if p.WD.Present() {
+			// nearest day with that weekday
+			k := (p.WD.Get() - int(rt.Weekday()) + 7) % 7
+			if k > 3 {
+				k -= 7
+			}
+			y, mo, dd = rt.AddDate(0, 0, k).Date()
+		}
 
 		t.FromValues(y, mo, dd, h, m, s, f, l)
-		return totm(&t, isValid)
+		return check(totm(&t, isValid))
 	}
 
 	if p.R.Present() {
-		y, mo, dd = rt.AddDate(0, 0, p.R.Get()).Date()
+		d, err := p.R.apply(rt, r.BusinessDay)
+		if err != nil {
+			return d, err
+		}
+		if p.R.Period().clock() && !p.H.Present() && !p.M.Present() && !p.S.Present() && !p.F.Present() {
+			d = d.In(l)
+			h, m, s = d.Clock()
+			f = d.Nanosecond()
+		}
+		y, mo, dd = d.Date()
 		t.FromValues(y, time.Month(mo), dd, h, m, s, f, l)
-		return totm(&t, isValid)
+		return check(totm(&t, isValid))
+	}
+
+	if p.WN.Present() {
+		wd := -1
+		if p.WD.Present() {
+			wd = p.WD.Get()
+		}
+		switch p.Y.Digits() {
+		case 0:
+			return find(newW(1, y, 0, p.WN, wd, h, m, s, f, l))
+		case 1:
+			return find(newW(10, y/10, p.Y.Get(), p.WN, wd, h, m, s, f, l))
+		case 2:
+			return find(newW(100, y/100, p.Y.Get(), p.WN, wd, h, m, s, f, l))
+		case 3:
+			return find(newW(1000, y/1000, p.Y.Get(), p.WN, wd, h, m, s, f, l))
+		case 4:
+			t, ok := newW(1, 0, p.Y.Get(), p.WN, wd, h, m, s, f, l).gen(0)
+			if !ok {
+				return time.Time{}, errInvalidDate
+			}
+			return check(t.Date(), nil)
+		}
+		return time.Time{}, errInvalidComponents
 	}
 
 	if p.J.Present() {
@@ -104,19 +173,19 @@ func Convert(rt time.Time, p *IDate) (time.Time, error) {
 		switch p.Y.Digits() {
 		case 0:
 			yf := newJ(1, y, 0, 1, p.J.Get(), h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 1:
 			yf := newJ(10, y/10, p.Y.Get(), 1, p.J.Get(), h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 2:
 			yf := newJ(100, y/100, p.Y.Get(), 1, p.J.Get(), h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 3:
 			yf := newJ(1000, y/1000, p.Y.Get(), 1, p.J.Get(), h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 4:
 			t.FromValues(p.Y.Get(), 1, p.J.Get(), h, m, s, f, l)
-			return totm(&t, isValidJJJ)
+			return check(totm(&t, isValidJJJ))
 
 		}
 		// year digits ???
@@ -129,25 +198,25 @@ func Convert(rt time.Time, p *IDate) (time.Time, error) {
 			switch p.Y.Digits() {
 			case 0:
 				yf := newY(1, y, 0, p.Mo.Get(), p.D.Get(), h, m, s, f, l)
-				return nearDateFind(rt, yf)
+				return find(yf)
 			case 1:
 				yf := newY(10, y/10, p.Y.Get(), p.Mo.Get(), p.D.Get(), h, m, s, f, l)
-				return nearDateFind(rt, yf)
+				return find(yf)
 			case 2:
 				yf := newY(100, y/100, p.Y.Get(), p.Mo.Get(), p.D.Get(), h, m, s, f, l)
-				return nearDateFind(rt, yf)
+				return find(yf)
 			case 3:
 				yf := newY(1000, y/1000, p.Y.Get(), p.Mo.Get(), p.D.Get(), h, m, s, f, l)
-				return nearDateFind(rt, yf)
+				return find(yf)
 			case 4:
 				t.FromValues(p.Y.Get(), time.Month(p.Mo.Get()), p.D.Get(), h, m, s, f, l)
-				return totm(&t, isValid)
+				return check(totm(&t, isValid))
 			}
 			// year digits ???
 			return time.Time{}, errInvalidComponents
 		}
 		mf := newM(y, mo, p.D.Get(), h, m, s, f, l)
-		return nearDateFind(rt, mf)
+		return find(mf)
 	}
 
 	// dd = 1
@@ -156,19 +225,19 @@ func Convert(rt time.Time, p *IDate) (time.Time, error) {
 		switch p.Y.Digits() {
 		case 0:
 			yf := newY(1, y, 0, p.Mo.Get(), 1, h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 1:
 			yf := newY(10, y/10, p.Y.Get(), p.Mo.Get(), 1, h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 2:
 			yf := newY(100, y/100, p.Y.Get(), p.Mo.Get(), 1, h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 3:
 			yf := newY(1000, y/1000, p.Y.Get(), p.Mo.Get(), 1, h, m, s, f, l)
-			return nearDateFind(rt, yf)
+			return find(yf)
 		case 4:
 			t.FromValues(p.Y.Get(), time.Month(p.Mo.Get()), 1, h, m, s, f, l)
-			return totm(&t, isValid)
+			return check(totm(&t, isValid))
 		}
 		// year digits ???
 		return time.Time{}, errInvalidComponents
@@ -180,16 +249,16 @@ func Convert(rt time.Time, p *IDate) (time.Time, error) {
 
 	case 1:
 		yf := newY(10, y/10, p.Y.Get(), 1, 1, h, m, s, f, l)
-		return nearDateFind(rt, yf)
+		return find(yf)
 	case 2:
 		yf := newY(100, y/100, p.Y.Get(), 1, 1, h, m, s, f, l)
-		return nearDateFind(rt, yf)
+		return find(yf)
 	case 3:
 		yf := newY(1000, y/1000, p.Y.Get(), 1, 1, h, m, s, f, l)
-		return nearDateFind(rt, yf)
+		return find(yf)
 	case 4:
 		t.FromValues(p.Y.Get(), 1, 1, h, m, s, f, l)
-		return totm(&t, isValid)
+		return check(totm(&t, isValid))
 
 	default:
 		// ??
@@ -197,37 +266,87 @@ func Convert(rt time.Time, p *IDate) (time.Time, error) {
 	}
 }
 
+// setCentury sets century of year y from chars in v,
+// year of 2 digits becomes year of 4 digits, without year century is year CC00
+func setCentury(y *Year, v []byte, blank bool) error {
+	var c Int
+	err := setField(&c, v, blank)
+	if err != nil || !c.Present() {
+		return err
+	}
+	switch y.Digits() {
+	case 0:
+		y.SetDI(4, c.Get()*100)
+	case 2:
+		y.SetDI(4, c.Get()*100+y.Get())
+	default:
+		return errInvalidComponents
+	}
+	return nil
+}
+
+// setQuarter sets month mo to first month of quarter from chars in v,
+// if month is present, it must be in quarter
+func setQuarter(mo *Int, v []byte, blank bool) error {
+	var q Int
+	err := setField(&q, v, blank)
+	if err != nil || !q.Present() {
+		return err
+	}
+	if q.Get() < 1 || q.Get() > 4 {
+		return errInvalidDate
+	}
+	if !mo.Present() {
+		mo.SetI(q.Get()*3 - 2)
+	} else if (mo.Get()+2)/3 != q.Get() {
+		return errInvalidDate
+	}
+	return nil
+}
+
 //////////////////////////////////////////////////////////////////
 
-func getFormatData(res, data, format []byte, mask byte) []byte {
-	res = res[:0]
-	for i := range format {
-		if format[i] == mask {
-			res = append(res, data[i])
-		}
+// isField returns if c is field char in format
+func isField(c byte) bool {
+	switch c {
+	case 'Y', 'M', 'D', 'J', 'h', 'm', 's', 'f', 'L', 'R':
+		return true
 	}
-	return res
+	return false
 }
 
-func getFormatNum(s setter, date, format []byte, mask byte) error {
-	res := make([]byte, 0, len(format))
+// chars from date, by format char
+type fieldData ['z' + 1][]byte
 
-	res = getFormatData(res, date, format, mask)
-	if len(res) == 0 {
+// setField sets s from v, empty field (and blank field, if blank is set) is absent
+func setField(s setter, v []byte, blank bool) error {
+	if len(v) == 0 || blank && len(bytes.TrimSpace(v)) == 0 {
 		return nil
 	}
-	return s.Set(res) //strconv.Atoi(string(res))
+	return s.Set(v)
 }
 
 // FromFormat converts date according to format to time.Time
 // date & format are treated as strings.
 //
-// format define date,
+// format define date, format and date are processed by runes (UTF-8 chars),
 // at positions of chars 'Y,M,D,J,h,m,s,f,L,R' in format,
 // are expected symbols
-// of 'year,month,day,julian day,hour,minute,second,fraction,timezone,relative days'
+// of 'year,month,day,julian day,hour,minute,second,fraction,timezone,relative offset'
 // in date. All other chars in format are ignored, corresponding positions in date also are ignored.
 //
+// With Resolver.FormatExt, 'U' is unit of relative offset, and
+// runs of chars 'N' and 'W' in format match month and weekday name (full or abbreviated,
+// case insensitive), and run of 'I' matches month in roman numerals, of any length.
+// Positions of following chars in format are shifted by difference between length of
+// name and length of run.
+// Weekday does not define date, but resolved date must be that weekday.
+// When weekday is only date component, nearest date with that weekday is returned.
+//
+// Digits are any Unicode decimal digits (Arabic-Indic, Devanagari, full width ...).
+// Fields may be padded with spaces, "  3" in place of "DDD" is day 3.
+// Blank fields (all spaces) are invalid, see Resolver.BlankAbsent.
+//
 // Accepted patterns are:
 //  Y      `\d{1,4}`              year, number of 'Y's is equal to number of year digits
 //  M      `\d{2}`                month
@@ -237,62 +356,174 @@ func getFormatNum(s setter, date, format []byte, mask byte) error {
 //  m      `\d{2}`                minute
 //  s      `\d{2}`                seconds
 //  f      `\d{1,9}`              fraction
-//  L      `[+-](\d\d):?(\d\d)`   timezone offset or
-//         `.+`                   timezone name
+//  L      `[+-](\d\d):?(\d\d)`   timezone offset (see Loc.Set for all offset forms) or
+//         `.+`                   timezone name or POSIX TZ string, resolved with DefaultZones
+//                                (IANA names, abbreviations, Windows zone IDs and aliases)
 //                                Special names 'l' & 'z' are Local & UTC zones
-//  R      `[+-]?\d+`             relative days
+//  R      `[+-]?\d+`             relative days or
+//         `[+-]?P.+`             ISO 8601 duration (see Rel.Set)
+//
+// With Resolver.FormatExt also:
+//  U      `[hdwmyb]`             unit of relative offset in 'R': hours, days, weeks, months,
+//                                years or business days
+//  N      `\pL+`                 month name, English (use Resolver.Locale for other languages)
+//  W      `\pL+`                 weekday name
+//  I      `[IVXivx]{1,4}`        month in roman numerals I..XII
 //
 // rt are reference time.
 func FromFormat(date, format []byte, rt time.Time) (time.Time, error) {
+	var r Resolver
+	p, err := parseFormat(date, format, &r)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return Convert(rt, &p)
+}
+
+// parseFormat fills IDate from date according to format,
+// timezone names, month & weekday names and blank fields are handled by policy of r
+func parseFormat(date, format []byte, r *Resolver) (IDate, error) {
 
 	//fmt.Printf("%s %s\n", date, format)
 	var p IDate
-	err := getFormatNum(&p.R, date, format, 'R')
-	if err != nil {
-		return time.Time{}, err
+	var f fieldData
+
+	// format & date are processed by runes
+	j := 0
+	for i := 0; i < len(format); {
+		c := format[i]
+		if c >= utf8.RuneSelf {
+			// not field char, skip rune in format & date
+			_, n := utf8.DecodeRune(format[i:])
+			_, m := utf8.DecodeRune(date[j:])
+			i += n
+			j += m
+			continue
+		}
+		i++
+		if r.FormatExt && (c == 'N' || c == 'W' || c == 'I') {
+			// names and roman numerals are variable width
+			for i < len(format) && format[i] == c {
+				i++
+			}
+			var v, n int
+			var ok bool
+			if c == 'I' {
+				v, n, ok = roman(date[j:])
+			} else {
+				v, n, ok = r.locale().name(date[j:], c == 'N')
+			}
+			if !ok {
+				return p, errInvalidName
+			}
+			if c == 'W' {
+				p.WD.SetI(v)
+			} else {
+				p.Mo.SetI(v)
+			}
+			j += n
+			continue
+		}
+		_, n := utf8.DecodeRune(date[j:])
+		if isField(c) || r.FormatExt && c == 'U' {
+			if j >= len(date) {
+				return p, errShortDate
+			}
+			f[c] = append(f[c], date[j:j+n]...)
+		}
+		j += n
 	}
-	err = getFormatNum(&p.Y, date, format, 'Y')
+
+	err := setFields(&p, &f, r)
+	return p, err
+}
+
+// setFields sets fields of p from chars collected by format char
+func setFields(p *IDate, f *fieldData, r *Resolver) error {
+	b := r.BlankAbsent
+	// relative offset with unit
+	err := setField(&p.R, append(f['R'], f['U']...), b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-	err = getFormatNum(&p.Mo, date, format, 'M')
+	err = setField(&p.Y, f['Y'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-	err = getFormatNum(&p.J, date, format, 'J')
+	err = setCentury(&p.Y, f['C'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-	err = getFormatNum(&p.D, date, format, 'D')
+	err = setField(&p.Mo, f['M'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-
-	err = getFormatNum(&p.H, date, format, 'h')
+	err = setQuarter(&p.Mo, f['Q'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-	err = getFormatNum(&p.M, date, format, 'm')
+	err = setField(&p.J, f['J'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-	err = getFormatNum(&p.S, date, format, 's')
+	err = setField(&p.D, f['D'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-	err = getFormatNum(&p.F, date, format, 'f')
+	err = setField(&p.WD, f['E'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
+	}
+	if len(f['E']) > 0 && p.WD.Present() {
+		// weekday number, 0 or 7 is Sunday
+		if p.WD.Get() < 0 || p.WD.Get() > 7 {
+			return errInvalidDate
+		}
+		p.WD.SetI(p.WD.Get() % 7)
+	}
+	for k, c := range [...]byte{WeekISO: 'V', WeekSunday: 'X', WeekMonday: 'O'} {
+		if len(f[c]) > 0 {
+			err = setField(&p.WN, f[c], b)
+			if err != nil {
+				return err
+			}
+			p.WN.Kind = WeekKind(k)
+		}
 	}
 
-	err = getFormatNum(&p.L, date, format, 'L')
+	err = setField(&p.H, f['h'], b)
 	if err != nil {
-		return time.Time{}, err
+		return err
 	}
-
-	r, e := Convert(rt, &p)
-	if e != nil {
-		return time.Time{}, e
+	if len(f['K']) > 0 {
+		// 12-hour clock
+		err = setField(&p.H, f['K'], b)
+		if err != nil {
+			return err
+		}
+		if p.H.Present() && (p.H.Get() < 1 || p.H.Get() > 12) {
+			return errInvalidDate
+		}
+	}
+	if len(f['A']) > 0 && p.H.Present() {
+		h := p.H.Get() % 12
+		if f['A'][0] == 'P' || f['A'][0] == 'p' {
+			h += 12
+		}
+		p.H.SetI(h)
+	}
+	err = setField(&p.M, f['m'], b)
+	if err != nil {
+		return err
 	}
-	return r, nil
+	err = setField(&p.S, f['s'], b)
+	if err != nil {
+		return err
+	}
+	err = setField(&p.F, f['f'], b)
+	if err != nil {
+		return err
+	}
+
+	return setField(zoneSetter{&p.L, r.zones()}, f['L'], b)
 }
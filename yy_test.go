@@ -173,7 +173,7 @@ var ta = []testData{
 	{
 		in:  "EET",
 		fmt: "LLL",
-		out: `2013-06-10 00:00:00.000000000 +0300 EST`,
+		out: `2013-06-10 00:00:00.000000000 +0200 EET`,
 	},
 	{
 		in:  "z",
@@ -0,0 +1,375 @@
+package yy
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// ZoneRegistry resolves timezone names to locations.
+//
+// Name is looked up in order:
+//   - special names 'z' & 'l' are UTC & Local zones, "Z" is UTC
+//   - UTC offsets ("+02:00", "-0530", "UTC+2"), as in Loc.Set
+//   - aliases registered with Register
+//   - abbreviations registered with RegisterAbbrev, and built in abbreviations (CEST, EDT, IST ...)
+//   - Windows zone IDs ("W. Europe Standard Time" ...)
+//   - POSIX TZ strings with daylight saving time or quoted name ("EST5EDT,M3.2.0,M11.1.0", "<+0330>-3:30" ...)
+//   - IANA names, loaded with Load
+//
+// Note that in POSIX TZ strings offset is west of UTC ("EST5EDT" is 5 hours west of UTC),
+// but "UTC-2" is UTC offset, 2 hours west of UTC, as in ISO 8601.
+//
+// Abbreviations are case insensitive and resolve to fixed zones named after abbreviation.
+// Many abbreviations are ambiguous (IST is India, Ireland or Israel), ambiguity is resolved
+// with Region, abbreviations without entry for Region resolve to most common meaning.
+//
+// Aliases and abbreviations are case insensitive, IANA names are as in Load.
+// Loaded zones are cached (failures are not), ZoneRegistry is safe for concurrent use.
+// Zero value of ZoneRegistry is ready to use.
+type ZoneRegistry struct {
+	// Region is ISO 3166 country code (e.g. "IN", "IE", "US"), case insensitive,
+	// used as hint for ambiguous abbreviations.
+	Region string
+
+	// Load loads IANA zone, if nil time.LoadLocation is used.
+	Load func(name string) (*time.Location, error)
+
+	mu      sync.RWMutex
+	aliases map[string]*time.Location
+	abbrevs map[string][]zoneAbbrev
+	cache   map[string]*time.Location
+}
+
+// DefaultZones is registry used by Loc.Set & Loc.SetName
+var DefaultZones = &ZoneRegistry{}
+
+// zoneAbbrev is meaning of abbreviation in region,
+// empty region is for regions without own entry
+type zoneAbbrev struct {
+	region string
+	l      *time.Location
+}
+
+func abbr(name string, off int, regions ...string) []zoneAbbrev {
+	l := time.FixedZone(name, off)
+	if len(regions) == 0 {
+		return []zoneAbbrev{{l: l}}
+	}
+	r := make([]zoneAbbrev, len(regions))
+	for i := range regions {
+		r[i] = zoneAbbrev{regions[i], l}
+	}
+	return r
+}
+
+func join(a ...[]zoneAbbrev) []zoneAbbrev {
+	var r []zoneAbbrev
+	for i := range a {
+		r = append(r, a[i]...)
+	}
+	return r
+}
+
+const oneHour = 3600
+
+// abbreviations, first entry is default meaning.
+// Abbreviations which are also IANA names (CET, EET, EST, MST ...) are fixed zones,
+// explicit "CET" is +01:00 also in summer.
+var abbrevs = map[string][]zoneAbbrev{
+	"UTC":  {{l: time.UTC}},
+	"UT":   {{l: time.UTC}},
+	"WET":  abbr("WET", 0),
+	"CET":  abbr("CET", 1*oneHour),
+	"MET":  abbr("MET", 1*oneHour),
+	"EET":  abbr("EET", 2*oneHour),
+	"WEST": abbr("WEST", 1*oneHour),
+	"CEST": abbr("CEST", 2*oneHour),
+	"MEST": abbr("MEST", 2*oneHour),
+	"EEST": abbr("EEST", 3*oneHour),
+	"MSK":  abbr("MSK", 3*oneHour),
+	"BST":  join(abbr("BST", 1*oneHour), abbr("BST", 6*oneHour, "BD")),
+	"IST":  join(abbr("IST", 5*oneHour+30*60, "IN"), abbr("IST", 1*oneHour, "IE"), abbr("IST", 2*oneHour, "IL")),
+	"IDT":  abbr("IDT", 3*oneHour),
+	"EST":  abbr("EST", -5*oneHour),
+	"EDT":  abbr("EDT", -4*oneHour),
+	"CST":  join(abbr("CST", -6*oneHour, "US", "CA", "MX"), abbr("CST", 8*oneHour, "CN", "TW"), abbr("CST", -5*oneHour, "CU")),
+	"CDT":  join(abbr("CDT", -5*oneHour, "US", "CA", "MX"), abbr("CDT", -4*oneHour, "CU")),
+	"MST":  abbr("MST", -7*oneHour),
+	"MDT":  abbr("MDT", -6*oneHour),
+	"PST":  join(abbr("PST", -8*oneHour, "US", "CA", "MX"), abbr("PST", 8*oneHour, "PH")),
+	"PDT":  abbr("PDT", -7*oneHour),
+	"AKST": abbr("AKST", -9*oneHour),
+	"AKDT": abbr("AKDT", -8*oneHour),
+	"HST":  abbr("HST", -10*oneHour),
+	"HDT":  abbr("HDT", -9*oneHour),
+	"AST":  join(abbr("AST", -4*oneHour, "CA", "PR"), abbr("AST", 3*oneHour, "SA", "IQ", "KW", "QA", "BH", "YE")),
+	"ADT":  abbr("ADT", -3*oneHour),
+	"NST":  abbr("NST", -3*oneHour-30*60),
+	"NDT":  abbr("NDT", -2*oneHour-30*60),
+	"BRT":  abbr("BRT", -3*oneHour),
+	"ART":  abbr("ART", -3*oneHour),
+	"CLT":  abbr("CLT", -4*oneHour),
+	"CLST": abbr("CLST", -3*oneHour),
+	"WAT":  abbr("WAT", 1*oneHour),
+	"CAT":  abbr("CAT", 2*oneHour),
+	"SAST": abbr("SAST", 2*oneHour),
+	"EAT":  abbr("EAT", 3*oneHour),
+	"GST":  abbr("GST", 4*oneHour),
+	"PKT":  abbr("PKT", 5*oneHour),
+	"NPT":  abbr("NPT", 5*oneHour+45*60),
+	"ICT":  abbr("ICT", 7*oneHour),
+	"WIB":  abbr("WIB", 7*oneHour),
+	"HKT":  abbr("HKT", 8*oneHour),
+	"SGT":  abbr("SGT", 8*oneHour),
+	"AWST": abbr("AWST", 8*oneHour),
+	"JST":  abbr("JST", 9*oneHour),
+	"KST":  abbr("KST", 9*oneHour),
+	"ACST": abbr("ACST", 9*oneHour+30*60),
+	"ACDT": abbr("ACDT", 10*oneHour+30*60),
+	"AEST": abbr("AEST", 10*oneHour),
+	"AEDT": abbr("AEDT", 11*oneHour),
+	"NZST": abbr("NZST", 12*oneHour),
+	"NZDT": abbr("NZDT", 13*oneHour),
+}
+
+// Windows zone IDs, mapped to IANA names as in CLDR windowsZones (territory 001)
+var windowsZones = map[string]string{
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"UTC-11":                          "Etc/GMT+11",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Mountain Standard Time":          "America/Denver",
+	"Central America Standard Time":   "America/Guatemala",
+	"Central Standard Time":           "America/Chicago",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Canada Central Standard Time":    "America/Regina",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Eastern Standard Time":           "America/New_York",
+	"US Eastern Standard Time":        "America/Indiana/Indianapolis",
+	"Venezuela Standard Time":         "America/Caracas",
+	"Atlantic Standard Time":          "America/Halifax",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"Argentina Standard Time":         "America/Argentina/Buenos_Aires",
+	"Azores Standard Time":            "Atlantic/Azores",
+	"Cape Verde Standard Time":        "Atlantic/Cape_Verde",
+	"UTC":                             "Etc/UTC",
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"Morocco Standard Time":           "Africa/Casablanca",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"FLE Standard Time":               "Europe/Kiev",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Arab Standard Time":              "Asia/Riyadh",
+	"Russian Standard Time":           "Europe/Moscow",
+	"E. Africa Standard Time":         "Africa/Nairobi",
+	"Iran Standard Time":              "Asia/Tehran",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"India Standard Time":             "Asia/Kolkata",
+	"Nepal Standard Time":             "Asia/Kathmandu",
+	"Bangladesh Standard Time":        "Asia/Dhaka",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"China Standard Time":             "Asia/Shanghai",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Korea Standard Time":             "Asia/Seoul",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"AUS Central Standard Time":       "Australia/Darwin",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"West Pacific Standard Time":      "Pacific/Port_Moresby",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"Tonga Standard Time":             "Pacific/Tongatapu",
+	"Line Islands Standard Time":      "Pacific/Kiritimati",
+}
+
+// regionZones are IANA zones of regions (ISO 3166 country codes), most populous first,
+// candidates for zone with UTC offset in region
+var regionZones = map[string][]string{
+	"US": {"America/New_York", "America/Chicago", "America/Denver", "America/Phoenix", "America/Los_Angeles", "America/Anchorage", "Pacific/Honolulu"},
+	"CA": {"America/Toronto", "America/Vancouver", "America/Edmonton", "America/Winnipeg", "America/Halifax", "America/Regina", "America/St_Johns"},
+	"MX": {"America/Mexico_City", "America/Tijuana", "America/Hermosillo", "America/Cancun"},
+	"BR": {"America/Sao_Paulo", "America/Manaus", "America/Noronha", "America/Rio_Branco"},
+	"AR": {"America/Argentina/Buenos_Aires"},
+	"CL": {"America/Santiago", "Pacific/Easter"},
+	"CO": {"America/Bogota"},
+	"PE": {"America/Lima"},
+	"CU": {"America/Havana"},
+	"PR": {"America/Puerto_Rico"},
+	"GB": {"Europe/London"},
+	"IE": {"Europe/Dublin"},
+	"PT": {"Europe/Lisbon", "Atlantic/Azores"},
+	"ES": {"Europe/Madrid", "Atlantic/Canary"},
+	"FR": {"Europe/Paris"},
+	"DE": {"Europe/Berlin"},
+	"NL": {"Europe/Amsterdam"},
+	"BE": {"Europe/Brussels"},
+	"CH": {"Europe/Zurich"},
+	"AT": {"Europe/Vienna"},
+	"IT": {"Europe/Rome"},
+	"PL": {"Europe/Warsaw"},
+	"CZ": {"Europe/Prague"},
+	"DK": {"Europe/Copenhagen"},
+	"SE": {"Europe/Stockholm"},
+	"NO": {"Europe/Oslo"},
+	"FI": {"Europe/Helsinki"},
+	"GR": {"Europe/Athens"},
+	"BG": {"Europe/Sofia"},
+	"RO": {"Europe/Bucharest"},
+	"UA": {"Europe/Kyiv"},
+	"TR": {"Europe/Istanbul"},
+	"RU": {"Europe/Moscow", "Asia/Yekaterinburg", "Asia/Novosibirsk", "Asia/Krasnoyarsk", "Asia/Irkutsk", "Asia/Vladivostok", "Europe/Kaliningrad", "Europe/Samara"},
+	"IL": {"Asia/Jerusalem"},
+	"EG": {"Africa/Cairo"},
+	"ZA": {"Africa/Johannesburg"},
+	"NG": {"Africa/Lagos"},
+	"KE": {"Africa/Nairobi"},
+	"SA": {"Asia/Riyadh"},
+	"AE": {"Asia/Dubai"},
+	"IR": {"Asia/Tehran"},
+	"IN": {"Asia/Kolkata"},
+	"PK": {"Asia/Karachi"},
+	"BD": {"Asia/Dhaka"},
+	"NP": {"Asia/Kathmandu"},
+	"TH": {"Asia/Bangkok"},
+	"VN": {"Asia/Ho_Chi_Minh"},
+	"ID": {"Asia/Jakarta", "Asia/Makassar", "Asia/Jayapura"},
+	"SG": {"Asia/Singapore"},
+	"PH": {"Asia/Manila"},
+	"CN": {"Asia/Shanghai"},
+	"HK": {"Asia/Hong_Kong"},
+	"TW": {"Asia/Taipei"},
+	"KR": {"Asia/Seoul"},
+	"JP": {"Asia/Tokyo"},
+	"AU": {"Australia/Sydney", "Australia/Melbourne", "Australia/Brisbane", "Australia/Perth", "Australia/Adelaide", "Australia/Darwin", "Australia/Hobart"},
+	"NZ": {"Pacific/Auckland", "Pacific/Chatham"},
+}
+
+// Register registers alias for location l, aliases are case insensitive
+func (z *ZoneRegistry) Register(alias string, l *time.Location) {
+	z.mu.Lock()
+	if z.aliases == nil {
+		z.aliases = make(map[string]*time.Location)
+	}
+	z.aliases[strings.ToUpper(alias)] = l
+	z.mu.Unlock()
+}
+
+// RegisterAbbrev registers abbreviation meaning offset seconds east of UTC in region.
+// Empty region registers default meaning.
+// Registered abbreviations take precedence over built in ones.
+func (z *ZoneRegistry) RegisterAbbrev(abbrev, region string, offset int) {
+	a := strings.ToUpper(abbrev)
+	z.mu.Lock()
+	if z.abbrevs == nil {
+		z.abbrevs = make(map[string][]zoneAbbrev)
+	}
+	e := zoneAbbrev{region, time.FixedZone(a, offset)}
+	if region == "" {
+		z.abbrevs[a] = append([]zoneAbbrev{e}, z.abbrevs[a]...)
+	} else {
+		z.abbrevs[a] = append(z.abbrevs[a], e)
+	}
+	z.mu.Unlock()
+}
+
+// pick abbreviation meaning for region,
+// if there is no entry for region, entry for empty region or (when first is set) first entry
+func pickAbbrev(a []zoneAbbrev, region string, first bool) *time.Location {
+	for i := range a {
+		if a[i].region == region && region != "" {
+			return a[i].l
+		}
+	}
+	for i := range a {
+		if a[i].region == "" {
+			return a[i].l
+		}
+	}
+	if first && len(a) > 0 {
+		return a[0].l
+	}
+	return nil
+}
+
+func (z *ZoneRegistry) load(name string) (*time.Location, error) {
+	if z.Load == nil {
+		return time.LoadLocation(name)
+	}
+	return z.Load(name)
+}
+
+// Lookup returns location for name
+func (z *ZoneRegistry) Lookup(name string) (*time.Location, error) {
+	switch name {
+	case "z":
+		return time.UTC, nil
+	case "l":
+		return time.Local, nil
+	case "Z":
+		return time.UTC, nil
+	}
+	if off, ok := offsetZone(name); ok {
+		return time.FixedZone("", off), nil
+	}
+
+	a := strings.ToUpper(name)
+	region := strings.ToUpper(z.Region)
+
+	z.mu.RLock()
+	l, ok := z.aliases[a]
+	if !ok {
+		l = pickAbbrev(z.abbrevs[a], region, false)
+		ok = l != nil
+	}
+	if !ok {
+		l, ok = z.cache[name]
+	}
+	z.mu.RUnlock()
+
+	if ok {
+		return l, nil
+	}
+
+	if l = pickAbbrev(abbrevs[a], region, true); l != nil {
+		return l, nil
+	}
+
+	var err error
+	if w, ok := windowsZones[name]; ok {
+		l, err = z.load(w)
+	} else if isPosixTZ(name) {
+		l, err = posixLocation(name)
+	} else {
+		l, err = z.load(name)
+	}
+
+	if err != nil {
+		// failures are not cached, names from data are unbounded,
+		// and failure of Load may be transient
+		return nil, err
+	}
+	z.mu.Lock()
+	if z.cache == nil {
+		z.cache = make(map[string]*time.Location)
+	}
+	z.cache[name] = l
+	z.mu.Unlock()
+	return l, nil
+}
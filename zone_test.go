@@ -0,0 +1,150 @@
+package yy
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestZoneRegistry(t *testing.T) {
+	at := time.Date(2013, time.June, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		region, name string
+		zone         string
+		offset       int
+	}{
+		{"", "z", "UTC", 0},
+		{"", "Europe/Sofia", "EEST", 3 * 3600},
+		// explicit abbreviation is fixed, also in summer
+		{"", "EET", "EET", 2 * 3600},
+		{"", "CET", "CET", 3600},
+		{"", "cet", "CET", 3600},
+		{"", "est", "EST", -5 * 3600},
+		{"", "CEST", "CEST", 2 * 3600},
+		{"", "cest", "CEST", 2 * 3600},
+		{"", "IST", "IST", 5*3600 + 1800},
+		{"IN", "IST", "IST", 5*3600 + 1800},
+		{"IE", "IST", "IST", 3600},
+		{"ie", "IST", "IST", 3600},
+		{"IL", "IST", "IST", 2 * 3600},
+		{"", "CST", "CST", -6 * 3600},
+		{"CN", "CST", "CST", 8 * 3600},
+		{"", "EST", "EST", -5 * 3600},
+		{"", "W. Europe Standard Time", "CEST", 2 * 3600},
+		{"", "Tokyo Standard Time", "JST", 9 * 3600},
+		{"", "AUS Eastern Standard Time", "AEST", 10 * 3600},
+	}
+
+	for _, tt := range tests {
+		z := ZoneRegistry{Region: tt.region}
+		l, err := z.Lookup(tt.name)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		name, off := at.In(l).Zone()
+		if name != tt.zone || off != tt.offset {
+			t.Errorf("%s/%s: got %s %d, want %s %d", tt.region, tt.name, name, off, tt.zone, tt.offset)
+		}
+	}
+}
+
+func TestZoneRegistryCustom(t *testing.T) {
+	var loads int
+	z := ZoneRegistry{
+		Load: func(name string) (*time.Location, error) {
+			loads++
+			if name == "Asia/Tokyo" {
+				return time.FixedZone("JST", 9*3600), nil
+			}
+			return nil, errors.New("unknown zone")
+		},
+	}
+	z.Register("HQ", time.FixedZone("HQ", 3600))
+	z.RegisterAbbrev("XST", "", -3*3600)
+	z.RegisterAbbrev("IST", "IE", 7200)
+
+	for i := 0; i < 3; i++ {
+		if _, err := z.Lookup("Asia/Tokyo"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := z.Lookup("Nowhere/City"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	// failures are not cached
+	if loads != 4 {
+		t.Errorf("loads %d, want 4", loads)
+	}
+
+	for _, alias := range []string{"HQ", "hq", "Hq"} {
+		l, err := z.Lookup(alias)
+		if err != nil || l.String() != "HQ" {
+			t.Errorf("alias %s: %v %v", alias, l, err)
+		}
+	}
+	l, err := z.Lookup("xst")
+	if err != nil || l.String() != "XST" {
+		t.Errorf("abbrev: %v %v", l, err)
+	}
+
+	// registered IST for IE overrides, other regions use built in
+	z.Region = "IE"
+	l, _ = z.Lookup("IST")
+	if _, off := time.Now().In(l).Zone(); off != 7200 {
+		t.Errorf("IST/IE offset %d", off)
+	}
+	z.Region = "IN"
+	l, _ = z.Lookup("IST")
+	if _, off := time.Now().In(l).Zone(); off != 5*3600+1800 {
+		t.Errorf("IST/IN offset %d", off)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				z.Lookup("Asia/Tokyo")
+				z.Register("HQ", time.UTC)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestZoneRegistryTransient(t *testing.T) {
+	fail := true
+	z := ZoneRegistry{
+		Load: func(name string) (*time.Location, error) {
+			if fail {
+				return nil, errors.New("temporary failure")
+			}
+			return time.FixedZone("JST", 9*3600), nil
+		},
+	}
+	if _, err := z.Lookup("Asia/Tokyo"); err == nil {
+		t.Fatal("expected error")
+	}
+	fail = false
+	if _, err := z.Lookup("Asia/Tokyo"); err != nil {
+		t.Errorf("failure is remembered: %v", err)
+	}
+}
+
+func TestResolverZones(t *testing.T) {
+	z := &ZoneRegistry{Region: "IE"}
+	r := Resolver{Zones: z}
+	got, err := r.FromFormat([]byte("10:00 IST"), []byte("hh:mm LLL"), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// reference is already 2013-06-11 in Ireland
+	want := time.Date(2013, time.June, 11, 9, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}